@@ -5,14 +5,49 @@ import (
 )
 
 type kubernetesConfig struct {
-	ConfigPaths          []string               `hcl:"config_paths,optional"`
-	ConfigPath           *string                `hcl:"config_path"`
-	ConfigContext        *string                `hcl:"config_context"`
-	CustomResourceTables []string               `hcl:"custom_resource_tables,optional"`
-	ManifestFilePaths    []string               `hcl:"manifest_file_paths,optional" steampipe:"watch"`
-	SourceType           *string                `hcl:"source_type"`
-	SourceTypes          []string               `hcl:"source_types,optional"`
-	HelmRenderedCharts   map[string]chartConfig `hcl:"helm_rendered_charts,optional"`
+	ConfigPaths                 []string               `hcl:"config_paths,optional"`
+	ConfigPath                  *string                `hcl:"config_path"`
+	ConfigContext               *string                `hcl:"config_context"`
+	Host                        *string                `hcl:"host"`
+	Token                       *string                `hcl:"token"`
+	TokenFile                   *string                `hcl:"token_file"`
+	ClientCertificate           *string                `hcl:"client_certificate"`
+	ClientKey                   *string                `hcl:"client_key"`
+	ClusterCACertificate        *string                `hcl:"cluster_ca_certificate"`
+	InsecureSkipTLSVerify       *bool                  `hcl:"insecure_skip_tls_verify"`
+	TLSServerName               *string                `hcl:"tls_server_name"`
+	ProxyURL                    *string                `hcl:"proxy_url"`
+	ExecTimeout                 *string                `hcl:"exec_timeout"`
+	ExecEnv                     map[string]string      `hcl:"exec_env,optional"`
+	QPS                         *float64               `hcl:"qps"`
+	Burst                       *int                   `hcl:"burst"`
+	RequestTimeout              *string                `hcl:"request_timeout"`
+	MaxErrorRetryAttempts       *int                   `hcl:"max_error_retry_attempts"`
+	MinErrorRetryDelay          *int                   `hcl:"min_error_retry_delay"`
+	MaxErrorRetryDelay          *int                   `hcl:"max_error_retry_delay"`
+	RetryableStatusCodes        []int                  `hcl:"retryable_status_codes,optional"`
+	ImpersonateUser             *string                `hcl:"impersonate_user"`
+	ImpersonateGroups           []string               `hcl:"impersonate_groups,optional"`
+	ImpersonateUID              *string                `hcl:"impersonate_uid"`
+	ImpersonateExtra            map[string][]string    `hcl:"impersonate_extra,optional"`
+	Namespaces                  []string               `hcl:"namespaces,optional"`
+	ExcludeNamespaces           []string               `hcl:"exclude_namespaces,optional"`
+	SecretDataMode              *string                `hcl:"secret_data_mode"`
+	CustomResourceTables        []string               `hcl:"custom_resource_tables,optional"`
+	CustomResourceDepth         *int                   `hcl:"custom_resource_column_depth"`
+	CustomResourceVersionTables *bool                  `hcl:"custom_resource_version_tables"`
+	CustomResourceTableAliases  *bool                  `hcl:"custom_resource_table_aliases"`
+	ManifestFilePaths           []string               `hcl:"manifest_file_paths,optional" steampipe:"watch"`
+	SnapshotPaths               []string               `hcl:"snapshot_paths,optional" steampipe:"watch"`
+	SourceType                  *string                `hcl:"source_type"`
+	SourceTypes                 []string               `hcl:"source_types,optional"`
+	HelmRenderedCharts          map[string]chartConfig `hcl:"helm_rendered_charts,optional"`
+
+	// HelmWatchPaths and SnapshotWatchPaths are not set in the config, but derived by setDerivedWatchPaths from the charts
+	// and values files in HelmRenderedCharts, and from the directories in SnapshotPaths, since only the top level string
+	// arguments can be watched for changes, and a directory is watched as a single file.
+	HelmWatchPaths     []string `steampipe:"watch"`
+	SnapshotWatchPaths []string `steampipe:"watch"`
 }
 
 type chartConfig struct {
@@ -20,6 +55,23 @@ type chartConfig struct {
 	ValuesFilePaths []string `hcl:"values_file_paths,optional" cty:"values_file_paths"`
 }
 
+// setDerivedWatchPaths sets the HelmWatchPaths and SnapshotWatchPaths of the config of the given connection, so the configured
+// charts, values files and snapshot directories are watched for changes along with the other paths tagged with `steampipe:"watch"`.
+//
+// The SDK has no hook to derive the config after it is parsed, so this is called while building the table map. It relies on
+// upsertConnectionData in the SDK (v5.14) building the table map of the connection, i.e. calling the TableMapFunc, before
+// extracting the watch paths from the same Connection.Config in updateConnectionWatchPaths.
+func setDerivedWatchPaths(connection *plugin.Connection) {
+	if connection == nil {
+		return
+	}
+	if config, ok := connection.Config.(kubernetesConfig); ok {
+		config.HelmWatchPaths = getHelmWatchPaths(config)
+		config.SnapshotWatchPaths = getSnapshotWatchPaths(config)
+		connection.Config = config
+	}
+}
+
 // GetConfig :: retrieve and cast connection config from query data
 func GetConfig(connection *plugin.Connection) kubernetesConfig {
 	if connection == nil || connection.Config == nil {
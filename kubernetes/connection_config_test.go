@@ -0,0 +1,46 @@
+package kubernetes
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+func TestSetDerivedWatchPaths(t *testing.T) {
+	snapshotDir := t.TempDir()
+	connection := &plugin.Connection{
+		Config: kubernetesConfig{
+			ManifestFilePaths:  []string{"/manifests/*.yaml"},
+			SnapshotPaths:      []string{snapshotDir, "/snapshots/pods.json"},
+			HelmRenderedCharts: map[string]chartConfig{"my-app": {ChartPath: "/charts/my-app", ValuesFilePaths: []string{"/values/dev.yaml"}}},
+		},
+	}
+	setDerivedWatchPaths(connection)
+
+	// Extract the watch paths from the config as the SDK does, i.e. from the fields tagged with `steampipe:"watch"`
+	var got []string
+	config := reflect.ValueOf(connection.Config)
+	for i := 0; i < config.NumField(); i++ {
+		if slices.Contains(strings.Split(config.Type().Field(i).Tag.Get("steampipe"), ","), "watch") {
+			if paths, ok := config.Field(i).Interface().([]string); ok {
+				got = append(got, paths...)
+			}
+		}
+	}
+
+	expected := []string{
+		"/manifests/*.yaml",
+		snapshotDir,
+		"/snapshots/pods.json",
+		"/charts/my-app/**/*",
+		"/values/dev.yaml",
+		filepath.Join(snapshotDir, "**", "*"),
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("watch paths = %v, expected %v", got, expected)
+	}
+}
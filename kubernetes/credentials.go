@@ -0,0 +1,378 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"golang.org/x/oauth2"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/transport"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/connection"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+// Utils functions to keep the credentials of the clients up to date in long-running sessions,
+// e.g. when the token of an exec plugin expires or the kubeconfig file is rotated.
+
+// clientCacheKeys are the cache keys of the clients of the connection, and of the kubeconfig and the cluster state they are built from
+var clientCacheKeys = []string{
+	"getK8Config",
+	"getK8ConfigRaw",
+	"getKubectlContext",
+	"k8sClient",
+	"GetNewClientCRD",
+	"GetNewClientCRDRaw",
+	"GetNewClientDynamic",
+	"getDiscoveryClients",
+	"getIncludedNamespaces",
+}
+
+// getHTTPClient returns the HTTP client used by the clients of the connection. If a request is rejected as unauthorized,
+// the transport is rebuilt from the latest kubeconfig and the request is retried once. If the latest kubeconfig points to
+// another API server, the cached clients are dropped instead, so that they are rebuilt by the next query.
+// The requests failing with transient errors are retried as configured in the connection config.
+func getHTTPClient(ctx context.Context, cc *connection.ConnectionCache, restconfig *rest.Config, kubernetesConfig kubernetesConfig) (*http.Client, error) {
+	rt, err := rest.TransportFor(restconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// The transport outlives the query creating the client, so only the values of the context are kept, e.g. the logger
+	ctx = context.WithoutCancel(ctx)
+	refresh := func() (http.RoundTripper, string, error) {
+		kubeconfig, err := loadK8Config(ctx, kubernetesConfig)
+		if err != nil {
+			return nil, "", err
+		}
+
+		restconfig, err := getRestConfig(ctx, cc, kubeconfig, kubernetesConfig)
+		if err != nil {
+			return nil, "", err
+		}
+
+		rt, err := rest.TransportFor(restconfig)
+		return rt, restconfig.Host, err
+	}
+	hostChanged := func() {
+		for _, key := range clientCacheKeys {
+			cc.Delete(ctx, key)
+		}
+	}
+
+	retry, err := getRetryConfig(kubernetesConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Transport: &retryTransport{
+			logger: plugin.Logger(ctx),
+			config: retry,
+			transport: &refreshingTransport{
+				logger:      plugin.Logger(ctx),
+				transport:   rt,
+				host:        restconfig.Host,
+				refresh:     refresh,
+				hostChanged: hostChanged,
+			},
+		},
+		Timeout: restconfig.Timeout,
+	}, nil
+}
+
+// refreshingTransport rebuilds the transport of the clients and retries the request once when the credentials are rejected
+type refreshingTransport struct {
+	logger hclog.Logger
+	// The API server the clients send the requests to, which the refreshed transport must target as well
+	host        string
+	refresh     func() (http.RoundTripper, string, error)
+	hostChanged func()
+
+	mu         sync.RWMutex
+	transport  http.RoundTripper
+	generation int
+}
+
+func (t *refreshingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.RLock()
+	rt, generation := t.transport, t.generation
+	t.mu.RUnlock()
+
+	resp, err := rt.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The requests with a body which cannot be sent again are not retried. The plugin only reads the resources though.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	rt, err = t.refreshTransport(generation)
+	if err != nil {
+		t.logger.Warn("refreshingTransport.RoundTrip", "failed to refresh the credentials", err)
+		return resp, nil
+	}
+
+	// Discard the unauthorized response, so that the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+
+	t.logger.Debug("refreshingTransport.RoundTrip", "retrying the request with refreshed credentials", req.URL.Path)
+	return rt.RoundTrip(retry)
+}
+
+// refreshTransport rebuilds the transport, unless it has already been rebuilt since the given generation by a concurrent request
+func (t *refreshingTransport) refreshTransport(generation int) (http.RoundTripper, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.generation != generation {
+		return t.transport, nil
+	}
+
+	rt, host, err := t.refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	// The URL of the requests cannot be changed by the transport, so the clients targeting the former API server are dropped
+	if host != t.host {
+		if t.hostChanged != nil {
+			t.hostChanged()
+		}
+		return nil, fmt.Errorf("the kubeconfig now points to %s instead of %s, the clients will be rebuilt by the next query", host, t.host)
+	}
+
+	t.transport = rt
+	t.generation++
+	return rt, nil
+}
+
+// applyExecSettings applies the exec_env and exec_timeout settings of the connection config to the exec credential plugin, if any
+func applyExecSettings(restconfig *rest.Config, kubernetesConfig kubernetesConfig) error {
+	if restconfig.ExecProvider == nil {
+		return nil
+	}
+
+	// Copy the exec config, since it can be shared with the kubeconfig
+	execConfig := *restconfig.ExecProvider
+	execConfig.Env = mergeExecEnv(execConfig.Env, kubernetesConfig.ExecEnv)
+	restconfig.ExecProvider = &execConfig
+
+	if kubernetesConfig.ExecTimeout == nil {
+		return nil
+	}
+	timeout, err := time.ParseDuration(*kubernetesConfig.ExecTimeout)
+	if err != nil {
+		return fmt.Errorf("invalid exec_timeout %q: %w", *kubernetesConfig.ExecTimeout, err)
+	}
+
+	// The plugin never runs in a terminal, so the exec plugins which cannot run without one are rejected upfront
+	if execConfig.InteractiveMode == clientcmdapi.AlwaysExecInteractiveMode {
+		return fmt.Errorf("exec_timeout is not supported for the exec plugin %q, which requires an interactive terminal", execConfig.Command)
+	}
+
+	// The exec plugins run by client-go cannot be cancelled, so the plugin is run by a token source instead.
+	// A token source cannot provide a client certificate though, so the plugins returning one are run by client-go, without a timeout.
+	fallback := rest.CopyConfig(restconfig)
+	tokenSource := transport.NewCachedTokenSource(&execTokenSource{
+		config:  execConfig,
+		cluster: execClusterInfo(restconfig),
+		timeout: timeout,
+	})
+	restconfig.ExecProvider = nil
+	restconfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &execTransport{
+			transport: transport.ResettableTokenSourceWrapTransport(tokenSource)(rt),
+			fallback: func() (http.RoundTripper, error) {
+				return rest.TransportFor(fallback)
+			},
+		}
+	})
+
+	return nil
+}
+
+// execTransport authenticates the requests with the token returned by an exec plugin, or with the transport built by client-go
+// once the exec plugin returns a client certificate instead
+type execTransport struct {
+	transport http.RoundTripper
+	fallback  func() (http.RoundTripper, error)
+
+	useFallback       atomic.Bool
+	fallbackOnce      sync.Once
+	fallbackTransport http.RoundTripper
+	fallbackErr       error
+}
+
+func (t *execTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.useFallback.Load() {
+		resp, err := t.transport.RoundTrip(req)
+		if !errors.Is(err, errExecClientCertificate) {
+			return resp, err
+		}
+		t.useFallback.Store(true)
+	}
+
+	t.fallbackOnce.Do(func() {
+		t.fallbackTransport, t.fallbackErr = t.fallback()
+	})
+	if t.fallbackErr != nil {
+		return nil, t.fallbackErr
+	}
+	return t.fallbackTransport.RoundTrip(req)
+}
+
+// mergeExecEnv returns the environment variables of the exec plugin, overridden by the given ones
+func mergeExecEnv(env []clientcmdapi.ExecEnvVar, overrides map[string]string) []clientcmdapi.ExecEnvVar {
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	merged := make([]clientcmdapi.ExecEnvVar, 0, len(env)+len(overrides))
+	for _, v := range env {
+		if _, ok := overrides[v.Name]; !ok {
+			merged = append(merged, v)
+		}
+	}
+	for _, name := range names {
+		merged = append(merged, clientcmdapi.ExecEnvVar{Name: name, Value: overrides[name]})
+	}
+	return merged
+}
+
+// execClusterInfo returns the cluster information passed to the exec plugins which require it, i.e. with provideClusterInfo set
+func execClusterInfo(restconfig *rest.Config) map[string]interface{} {
+	if !restconfig.ExecProvider.ProvideClusterInfo {
+		return nil
+	}
+
+	cluster := map[string]interface{}{
+		"server":                   restconfig.Host,
+		"tls-server-name":          restconfig.TLSClientConfig.ServerName,
+		"insecure-skip-tls-verify": restconfig.TLSClientConfig.Insecure,
+	}
+	if len(restconfig.TLSClientConfig.CAData) > 0 {
+		cluster["certificate-authority-data"] = base64.StdEncoding.EncodeToString(restconfig.TLSClientConfig.CAData)
+	} else if restconfig.TLSClientConfig.CAFile != "" {
+		if data, err := os.ReadFile(restconfig.TLSClientConfig.CAFile); err == nil {
+			cluster["certificate-authority-data"] = base64.StdEncoding.EncodeToString(data)
+		}
+	}
+	return cluster
+}
+
+// errExecClientCertificate is returned by execTokenSource if the exec plugin returns a client certificate instead of a token
+var errExecClientCertificate = errors.New("exec plugin returned a client certificate instead of a token")
+
+// execTokenSource runs an exec credential plugin, e.g. `aws eks get-token`, with a timeout
+type execTokenSource struct {
+	config  clientcmdapi.ExecConfig
+	cluster map[string]interface{}
+	timeout time.Duration
+}
+
+type execCredential struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Status     *struct {
+		Token               string     `json:"token"`
+		ClientKeyData       string     `json:"clientKeyData"`
+		ExpirationTimestamp *time.Time `json:"expirationTimestamp"`
+	} `json:"status"`
+}
+
+func (ts *execTokenSource) Token() (*oauth2.Token, error) {
+	spec := map[string]interface{}{"interactive": false}
+	if ts.cluster != nil {
+		spec["cluster"] = ts.cluster
+	}
+	info, err := json.Marshal(map[string]interface{}{
+		"apiVersion": ts.config.APIVersion,
+		"kind":       "ExecCredential",
+		"spec":       spec,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	env := os.Environ()
+	for _, v := range ts.config.Env {
+		env = append(env, v.Name+"="+v.Value)
+	}
+	env = append(env, "KUBERNETES_EXEC_INFO="+string(info))
+
+	ctx, cancel := context.WithTimeout(context.Background(), ts.timeout)
+	defer cancel()
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, ts.config.Command, ts.config.Args...)
+	cmd.Env = env
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) && ts.config.InstallHint != "" {
+			return nil, fmt.Errorf("exec plugin %q not found\n\n%s", ts.config.Command, ts.config.InstallHint)
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("exec plugin %q did not return credentials within exec_timeout %s", ts.config.Command, ts.timeout)
+		}
+		return nil, fmt.Errorf("exec plugin %q failed: %w: %s", ts.config.Command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return parseExecCredential(stdout.Bytes(), ts.config.APIVersion)
+}
+
+// parseExecCredential returns the token of the ExecCredential printed by an exec plugin
+func parseExecCredential(data []byte, apiVersion string) (*oauth2.Token, error) {
+	var cred execCredential
+	if err := json.Unmarshal(data, &cred); err != nil {
+		return nil, fmt.Errorf("decoding exec plugin output: %w", err)
+	}
+	if cred.APIVersion != apiVersion {
+		return nil, fmt.Errorf("exec plugin is configured to use API version %s, plugin returned version %s", apiVersion, cred.APIVersion)
+	}
+	if cred.Status == nil {
+		return nil, fmt.Errorf("exec plugin didn't return a status field")
+	}
+	if cred.Status.Token == "" {
+		if cred.Status.ClientKeyData != "" {
+			return nil, errExecClientCertificate
+		}
+		return nil, fmt.Errorf("exec plugin didn't return a token")
+	}
+
+	token := &oauth2.Token{AccessToken: cred.Status.Token, TokenType: "Bearer"}
+	if cred.Status.ExpirationTimestamp != nil {
+		token.Expiry = *cred.Status.ExpirationTimestamp
+	}
+	return token, nil
+}
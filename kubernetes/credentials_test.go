@@ -0,0 +1,227 @@
+package kubernetes
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func statusRoundTripper(status int, calls *int) http.RoundTripper {
+	return roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		*calls++
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})
+}
+
+func TestRefreshingTransport(t *testing.T) {
+	var staleCalls, freshCalls, refreshes int
+	rt := &refreshingTransport{
+		logger:    hclog.NewNullLogger(),
+		transport: statusRoundTripper(http.StatusUnauthorized, &staleCalls),
+		refresh: func() (http.RoundTripper, string, error) {
+			refreshes++
+			return statusRoundTripper(http.StatusOK, &freshCalls), "", nil
+		},
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://10.0.0.1:6443/api/v1/pods", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("RoundTrip() = %v, %v; want the retried response", resp, err)
+	}
+	if staleCalls != 1 || freshCalls != 1 || refreshes != 1 {
+		t.Errorf("calls = %d stale, %d fresh, %d refreshes; want 1 of each", staleCalls, freshCalls, refreshes)
+	}
+
+	// The refreshed transport is used for the next requests
+	if _, err := rt.RoundTrip(req); err != nil || freshCalls != 2 || refreshes != 1 {
+		t.Errorf("RoundTrip() again = %v; want the refreshed transport to be reused", err)
+	}
+
+	// The request is retried once only if the refreshed credentials are rejected too
+	var unauthorizedCalls int
+	rt = &refreshingTransport{
+		logger:    hclog.NewNullLogger(),
+		transport: statusRoundTripper(http.StatusUnauthorized, &unauthorizedCalls),
+		refresh: func() (http.RoundTripper, string, error) {
+			return statusRoundTripper(http.StatusUnauthorized, &unauthorizedCalls), "", nil
+		},
+	}
+	if resp, err := rt.RoundTrip(req); err != nil || resp.StatusCode != http.StatusUnauthorized || unauthorizedCalls != 2 {
+		t.Errorf("RoundTrip() = %v, %v after %d calls; want an unauthorized response after 2 calls", resp, err, unauthorizedCalls)
+	}
+}
+
+func TestRefreshingTransportHostChange(t *testing.T) {
+	var staleCalls, freshCalls, hostChanges int
+	rt := &refreshingTransport{
+		logger:    hclog.NewNullLogger(),
+		host:      "https://10.0.0.1:6443",
+		transport: statusRoundTripper(http.StatusUnauthorized, &staleCalls),
+		refresh: func() (http.RoundTripper, string, error) {
+			return statusRoundTripper(http.StatusOK, &freshCalls), "https://10.0.0.2:6443", nil
+		},
+		hostChanged: func() {
+			hostChanges++
+		},
+	}
+
+	// The request is not retried against the former API server, and the clients are dropped instead
+	req, _ := http.NewRequest(http.MethodGet, "https://10.0.0.1:6443/api/v1/pods", nil)
+	if resp, err := rt.RoundTrip(req); err != nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("RoundTrip() = %v, %v; want the unauthorized response", resp, err)
+	}
+	if freshCalls != 0 || hostChanges != 1 {
+		t.Errorf("calls = %d fresh, %d host changes; want 0 and 1", freshCalls, hostChanges)
+	}
+}
+
+func TestMergeExecEnv(t *testing.T) {
+	env := []clientcmdapi.ExecEnvVar{{Name: "AWS_PROFILE", Value: "default"}, {Name: "AWS_REGION", Value: "us-east-1"}}
+	merged := mergeExecEnv(env, map[string]string{"AWS_PROFILE": "readonly", "AWS_STS_REGIONAL_ENDPOINTS": "regional"})
+
+	expected := []clientcmdapi.ExecEnvVar{
+		{Name: "AWS_REGION", Value: "us-east-1"},
+		{Name: "AWS_PROFILE", Value: "readonly"},
+		{Name: "AWS_STS_REGIONAL_ENDPOINTS", Value: "regional"},
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("mergeExecEnv() = %+v, want %+v", merged, expected)
+	}
+}
+
+func TestParseExecCredential(t *testing.T) {
+	apiVersion := "client.authentication.k8s.io/v1beta1"
+	tests := []struct {
+		name       string
+		data       string
+		wantToken  string
+		wantExpiry time.Time
+		wantErr    bool
+		wantErrIs  error
+	}{
+		{
+			name:       "token with expiration",
+			data:       `{"apiVersion":"client.authentication.k8s.io/v1beta1","kind":"ExecCredential","status":{"token":"k8s-aws-v1.abc","expirationTimestamp":"2026-10-18T17:00:00Z"}}`,
+			wantToken:  "k8s-aws-v1.abc",
+			wantExpiry: time.Date(2026, 10, 18, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "token without expiration",
+			data:      `{"apiVersion":"client.authentication.k8s.io/v1beta1","kind":"ExecCredential","status":{"token":"abc"}}`,
+			wantToken: "abc",
+		},
+		{
+			name:    "other API version",
+			data:    `{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"abc"}}`,
+			wantErr: true,
+		},
+		{
+			name:      "cert/key pair",
+			data:      `{"apiVersion":"client.authentication.k8s.io/v1beta1","kind":"ExecCredential","status":{"clientCertificateData":"cert","clientKeyData":"key"}}`,
+			wantErr:   true,
+			wantErrIs: errExecClientCertificate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := parseExecCredential([]byte(tt.data), apiVersion)
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("parseExecCredential() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseExecCredential() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if token.AccessToken != tt.wantToken || !token.Expiry.Equal(tt.wantExpiry) {
+				t.Errorf("parseExecCredential() = %q expiring %v, want %q expiring %v", token.AccessToken, token.Expiry, tt.wantToken, tt.wantExpiry)
+			}
+		})
+	}
+}
+
+func TestExecTokenSourceTimeout(t *testing.T) {
+	ts := &execTokenSource{
+		config:  clientcmdapi.ExecConfig{Command: "sleep", Args: []string{"5"}, APIVersion: "client.authentication.k8s.io/v1beta1"},
+		timeout: 100 * time.Millisecond,
+	}
+
+	start := time.Now()
+	if _, err := ts.Token(); err == nil || !strings.Contains(err.Error(), "exec_timeout") {
+		t.Errorf("Token() error = %v, want an exec_timeout error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Token() took %v, want the plugin to be stopped after the timeout", elapsed)
+	}
+}
+
+func TestExecTransportFallback(t *testing.T) {
+	var tokenCalls, fallbackCalls, fallbacks int
+	rt := &execTransport{
+		transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			tokenCalls++
+			return nil, errExecClientCertificate
+		}),
+		fallback: func() (http.RoundTripper, error) {
+			fallbacks++
+			return statusRoundTripper(http.StatusOK, &fallbackCalls), nil
+		},
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://10.0.0.1:6443/api/v1/pods", nil)
+	for range 2 {
+		if resp, err := rt.RoundTrip(req); err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("RoundTrip() = %v, %v; want the response of the fallback transport", resp, err)
+		}
+	}
+
+	// The exec plugin is not run again once it returned a client certificate
+	if tokenCalls != 1 || fallbackCalls != 2 || fallbacks != 1 {
+		t.Errorf("calls = %d token, %d fallback, %d fallbacks built; want 1, 2 and 1", tokenCalls, fallbackCalls, fallbacks)
+	}
+}
+
+func TestApplyExecSettings(t *testing.T) {
+	timeout := "30s"
+
+	restconfig := &rest.Config{ExecProvider: &clientcmdapi.ExecConfig{Command: "aws", APIVersion: "client.authentication.k8s.io/v1beta1"}}
+	if err := applyExecSettings(restconfig, kubernetesConfig{ExecTimeout: &timeout}); err != nil {
+		t.Fatalf("applyExecSettings() error = %v", err)
+	}
+	if restconfig.ExecProvider != nil || restconfig.WrapTransport == nil {
+		t.Errorf("applyExecSettings() = %+v; want the exec plugin to be run by a token source", restconfig)
+	}
+
+	// The interactive exec plugins cannot run without a terminal
+	restconfig = &rest.Config{ExecProvider: &clientcmdapi.ExecConfig{Command: "kubelogin", InteractiveMode: clientcmdapi.AlwaysExecInteractiveMode}}
+	if err := applyExecSettings(restconfig, kubernetesConfig{ExecTimeout: &timeout}); err == nil {
+		t.Errorf("applyExecSettings(interactive) expected an error")
+	}
+}
+
+func TestExecTokenSourceInstallHint(t *testing.T) {
+	ts := &execTokenSource{
+		config:  clientcmdapi.ExecConfig{Command: "steampipe-missing-auth-plugin", APIVersion: "client.authentication.k8s.io/v1beta1", InstallHint: "Install it with brew install auth-plugin"},
+		timeout: time.Second,
+	}
+
+	if _, err := ts.Token(); err == nil || !strings.Contains(err.Error(), ts.config.InstallHint) {
+		t.Errorf("Token() error = %v, want the install hint", err)
+	}
+}
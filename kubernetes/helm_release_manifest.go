@@ -0,0 +1,146 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+// Utils functions for the manifests of the deployed Helm releases
+
+type helmReleaseObject struct {
+	Release HelmRelease
+	Object  *unstructured.Unstructured
+}
+
+// getHelmReleaseObjects returns the objects defined in the manifest of every deployed Helm release in the cluster
+func getHelmReleaseObjects(ctx context.Context, d *plugin.QueryData) ([]helmReleaseObject, error) {
+	objects, err := helmReleaseObjectsCached(ctx, d, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if objects != nil {
+		return objects.([]helmReleaseObject), nil
+	}
+	return nil, nil
+}
+
+// Cached form of the Helm release objects.
+var helmReleaseObjectsCached = plugin.HydrateFunc(helmReleaseObjectsUncached).Memoize()
+
+// helmReleaseObjectsUncached is the actual implementation of getHelmReleaseObjects, which should
+// be run only once per connection. Do not call this directly, use
+// getHelmReleaseObjects instead.
+func helmReleaseObjectsUncached(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (any, error) {
+	// Read the config
+	kubernetesConfig := GetConfig(d.Connection)
+
+	sources := getSourceTypes(kubernetesConfig)
+
+	if !slices.Contains(sources, HelmReleaseSourceType.String()) {
+		return nil, nil
+	}
+
+	plugin.Logger(ctx).Debug("helmReleaseObjectsUncached", "Parsing Helm release manifests", "connection", d.Connection.Name)
+
+	// List the latest revision of all the releases in all the namespaces
+	revisions, err := listHelmReleaseRevisions(ctx, d, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	mapper, err := getRESTMapper(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []helmReleaseObject
+	for _, r := range filterLatestHelmReleases(revisions) {
+		// Only the deployed releases reflect what Helm believes is installed
+		if r.Info == nil || r.Info.Status != release.StatusDeployed {
+			continue
+		}
+
+		for _, resource := range yamlDocSeparator.Split(r.Manifest, -1) {
+			// Skip if no kind defined
+			if !(strings.Contains(resource, "kind:") || strings.Contains(resource, "\"kind\":")) {
+				continue
+			}
+
+			obj := &unstructured.Unstructured{}
+			err = yaml.Unmarshal([]byte(resource), obj)
+			if err != nil {
+				plugin.Logger(ctx).Error("helmReleaseObjectsUncached", "unmarshal_error", err, "release", r.Name)
+				return nil, err
+			}
+
+			// Helm installs the namespaced objects without a namespace into the namespace of the release
+			if obj.GetNamespace() == "" && mapper != nil {
+				mapping, err := mapper.RESTMapping(obj.GroupVersionKind().GroupKind(), obj.GroupVersionKind().Version)
+				if err == nil && mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+					obj.SetNamespace(r.Namespace)
+				}
+			}
+
+			objects = append(objects, helmReleaseObject{
+				Release: r,
+				Object:  obj,
+			})
+		}
+	}
+
+	return objects, nil
+}
+
+// Get the parsed contents of the deployed Helm release manifests.
+func getHelmReleaseManifestContent(ctx context.Context, d *plugin.QueryData) ([]parsedContent, error) {
+	conn, err := helmReleaseManifestContentCached(ctx, d, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if conn != nil {
+		return conn.([]parsedContent), nil
+	}
+	return nil, nil
+}
+
+// Cached form of the parsed Helm release manifest content.
+var helmReleaseManifestContentCached = plugin.HydrateFunc(helmReleaseManifestContentUncached).Memoize()
+
+// helmReleaseManifestContentUncached is the actual implementation of getHelmReleaseManifestContent, which should
+// be run only once per connection. Do not call this directly, use
+// getHelmReleaseManifestContent instead.
+func helmReleaseManifestContentUncached(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (any, error) {
+	objects, err := getHelmReleaseObjects(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsedContents []parsedContent
+	for _, o := range objects {
+		// Convert the content to concrete type based on the resource kind
+		targetObj, err := convertUnstructuredDataToType(o.Object.DeepCopy())
+		if err != nil {
+			plugin.Logger(ctx).Error("helmReleaseManifestContentUncached", "failed to convert content into a concrete type", err, "release", o.Release.Name)
+			return nil, err
+		}
+
+		parsedContents = append(parsedContents, parsedContent{
+			ParsedData: targetObj,
+			Kind:       o.Object.GetKind(),
+			SourceType: fmt.Sprintf("%s:%s", HelmReleaseSourceType, o.Release.Name),
+		})
+	}
+
+	return parsedContents, nil
+}
@@ -95,28 +95,18 @@ func getHelmRenderedTemplatesUncached(ctx context.Context, d *plugin.QueryData,
 				// Add the processed Helm render configs into processedHelmConfigs to avoid duplicate entries
 				processedHelmConfigs = append(processedHelmConfigs, name)
 
-				client := newClient()
-				client.ReleaseName = name
-				client.Namespace = "default" // TODO: Update this to use namespace defined in the current context
-
-				vals := &values.Options{}
-				if len(c.ValuesFilePaths) > 0 {
-					vals.ValueFiles = c.ValuesFilePaths
+				renders, err := getHelmChartRenders(ctx, d)
+				if err != nil {
+					return nil, err
 				}
 
-				manifest, _, err := runInstall([]string{c.ChartPath}, client, vals)
-				if err != nil {
-					plugin.Logger(ctx).Debug("getHelmRenderedTemplatesUncached", "run_install_error", err, "connection", d.Connection.Name)
-					// return nil, err
+				render := renders[name]
+				if render.Err != nil {
+					plugin.Logger(ctx).Debug("getHelmRenderedTemplatesUncached", "run_install_error", render.Err, "connection", d.Connection.Name)
 					continue
 				}
 
-				splitManifest := yamlDocSeparator.Split(manifest.Manifest, -1)
-				for _, content := range splitManifest {
-					if len(content) == 0 {
-						continue
-					}
-
+				for _, content := range render.Documents {
 					renderedTemplates = append(renderedTemplates, HelmRenderedTemplate{
 						Data:      content,
 						Chart:     chart.Chart,
@@ -130,6 +120,62 @@ func getHelmRenderedTemplatesUncached(ctx context.Context, d *plugin.QueryData,
 	return renderedTemplates, nil
 }
 
+// helmChartRender is the result of rendering a chart configured in helm_rendered_charts
+type helmChartRender struct {
+	Documents []string
+	Err       error
+}
+
+// getHelmChartRenders returns the result of rendering each chart configured in helm_rendered_charts, by config key.
+// The charts which fail to render are returned along with their error, e.g. to be reported by kubernetes_connection_info.
+func getHelmChartRenders(ctx context.Context, d *plugin.QueryData) (map[string]helmChartRender, error) {
+	renders, err := getHelmChartRendersCached(ctx, d, nil)
+	if err != nil {
+		return nil, err
+	}
+	return renders.(map[string]helmChartRender), nil
+}
+
+// Cached form of the chart renders.
+var getHelmChartRendersCached = plugin.HydrateFunc(getHelmChartRendersUncached).Memoize()
+
+// getHelmChartRendersUncached is the actual implementation of getHelmChartRenders, which should
+// be run only once per connection. Do not call this directly, use
+// getHelmChartRenders instead.
+func getHelmChartRendersUncached(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (any, error) {
+	renders := map[string]helmChartRender{}
+	for name, c := range GetConfig(d.Connection).HelmRenderedCharts {
+		documents, err := renderHelmChart(name, c)
+		renders[name] = helmChartRender{Documents: documents, Err: err}
+	}
+	return renders, nil
+}
+
+// renderHelmChart renders the given chart with its values files, and returns the non-empty documents of the resulting manifest
+func renderHelmChart(name string, c chartConfig) ([]string, error) {
+	client := newClient()
+	client.ReleaseName = name
+	client.Namespace = "default" // TODO: Update this to use namespace defined in the current context
+
+	vals := &values.Options{}
+	if len(c.ValuesFilePaths) > 0 {
+		vals.ValueFiles = c.ValuesFilePaths
+	}
+
+	manifest, _, err := runInstall([]string{c.ChartPath}, client, vals)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []string
+	for _, content := range yamlDocSeparator.Split(manifest.Manifest, -1) {
+		if len(content) > 0 {
+			documents = append(documents, content)
+		}
+	}
+	return documents, nil
+}
+
 // Utils functions from formatting the rendered template contents
 
 // Get the parsed contents of the given files.
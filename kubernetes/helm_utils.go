@@ -5,13 +5,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path"
 	"slices"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 
-	helmClient "github.com/mittwald/go-helm-client"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 )
@@ -46,18 +55,7 @@ func parsedHelmChartUncached(ctx context.Context, d *plugin.QueryData, _ *plugin
 	// Read the config
 	kubernetesConfig := GetConfig(d.Connection)
 
-	// Check for the sourceTypes argument in the config.
-	// Default set to include values.
-	var sources = All.ToSourceTypes()
-	if kubernetesConfig.SourceTypes != nil {
-		sources = kubernetesConfig.SourceTypes
-	}
-	// TODO: Remove once `SourceType` is obsolete
-	if kubernetesConfig.SourceTypes == nil && kubernetesConfig.SourceType != nil {
-		if *kubernetesConfig.SourceType != "all" { // if is all, sources is already set by default
-			sources = []string{*kubernetesConfig.SourceType}
-		}
-	}
+	sources := getSourceTypes(kubernetesConfig)
 
 	if !slices.Contains(sources, "helm") {
 		return nil, nil
@@ -131,27 +129,171 @@ func getUniqueValueFilesFromConfig(ctx context.Context, d *plugin.QueryData) []s
 	return filePaths
 }
 
-// getHelmClient creates the client for Helm
-func getHelmClient(ctx context.Context, namespace string) (helmClient.Client, error) {
-	// Return nil if no namespace provided
-	if namespace == "" {
+// getHelmWatchPaths returns the charts and values files configured in the given config, to be watched for changes.
+// Any change to the watched files clears the connection cache, so the memoized charts and renders are reloaded on the next query.
+func getHelmWatchPaths(config kubernetesConfig) []string {
+	var watchPaths []string
+	for _, c := range config.HelmRenderedCharts {
+		switch {
+		case c.ChartPath == "":
+		case isHelmChartArchive(c.ChartPath):
+			watchPaths = append(watchPaths, c.ChartPath)
+		default:
+			watchPaths = append(watchPaths, path.Join(c.ChartPath, "**", "*"))
+		}
+		watchPaths = append(watchPaths, c.ValuesFilePaths...)
+	}
+
+	// Sort and remove the duplicates, since the same chart can be configured for multiple renders
+	sort.Strings(watchPaths)
+	return slices.Compact(watchPaths)
+}
+
+// isHelmChartArchive returns true if the given chart path is a packaged chart, e.g. my-app-1.0.0.tgz, rather than a chart directory.
+// The charts which do not exist yet are considered packaged if they have the extension of an archive.
+func isHelmChartArchive(chartPath string) bool {
+	if info, err := os.Stat(chartPath); err == nil {
+		return !info.IsDir()
+	}
+	return strings.HasSuffix(chartPath, ".tgz") || strings.HasSuffix(chartPath, ".tar.gz")
+}
+
+// loadHelmChartForConfig loads a fresh copy of the chart configured in the given render config, along with the merged values of
+// its values override files. As done while rendering the chart, the subcharts disabled by the conditions and tags are removed
+// from the returned chart, and the aliased subcharts are renamed.
+func loadHelmChartForConfig(c chartConfig) (*chart.Chart, map[string]interface{}, error) {
+	// Load a fresh copy of the chart, since processing the dependencies modifies the chart
+	chrt, err := loader.Load(c.ChartPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vals, err := (&values.Options{ValueFiles: c.ValuesFilePaths}).MergeValues(getter.All(settings))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := chartutil.ProcessDependenciesWithMerge(chrt, vals); err != nil {
+		return nil, nil, err
+	}
+
+	return chrt, vals, nil
+}
+
+// getHelmSubchartPath returns the directory path of the given subchart, e.g. /path/to/chart/charts/redis
+func getHelmSubchartPath(root *chart.Chart, rootPath string, c *chart.Chart) string {
+	relativePath := strings.TrimPrefix(c.ChartFullPath(), root.ChartFullPath())
+	return path.Join(rootPath, relativePath)
+}
+
+type HelmRelease struct {
+	release.Release
+	StorageDriver string
+}
+
+// getHelmReleaseStorageDrivers returns the Helm storage drivers for the given namespace, using the clientset of the connection.
+// Releases can be stored in either secrets (the default) or configmaps, depending on the HELM_DRIVER used to install them,
+// so both the drivers are returned. An empty namespace returns the drivers for all the namespaces.
+func getHelmReleaseStorageDrivers(ctx context.Context, d *plugin.QueryData, namespace string) ([]driver.Driver, error) {
+	// Get the client for querying the K8s APIs for the provided context.
+	// If the connection is not configured for the deployed resources, the client will return nil.
+	clientset, err := GetNewClientset(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	if clientset == nil {
 		return nil, nil
 	}
 
-	// Set the namespace if specified.
-	// By default current namespace context is used.
-	options := &helmClient.Options{
-		Namespace: namespace,
+	return []driver.Driver{
+		driver.NewSecrets(clientset.CoreV1().Secrets(namespace)),
+		driver.NewConfigMaps(clientset.CoreV1().ConfigMaps(namespace)),
+	}, nil
+}
+
+// listHelmReleaseRevisions returns all the revisions of the releases stored in the given namespace.
+// If the release name is provided, only the revisions of the given release are returned.
+func listHelmReleaseRevisions(ctx context.Context, d *plugin.QueryData, namespace string, releaseName string) ([]HelmRelease, error) {
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, namespace) {
+		return nil, nil
+	}
+
+	// The releases of all the namespaces are listed from the single namespace included in the connection, if any
+	if namespace == "" {
+		if namespaces := getIncludedNamespaces(ctx, d); len(namespaces) == 1 {
+			namespace = namespaces[0]
+		}
 	}
 
-	// Create client
-	client, err := helmClient.New(options)
+	drivers, err := getHelmReleaseStorageDrivers(ctx, d, namespace)
 	if err != nil {
-		plugin.Logger(ctx).Error("getHelmClient", "client_error", err)
 		return nil, err
 	}
 
-	return client, nil
+	labels := map[string]string{"owner": "helm"}
+	if releaseName != "" {
+		labels["name"] = releaseName
+	}
+
+	var releases []HelmRelease
+	for _, drv := range drivers {
+		items, err := drv.Query(labels)
+		if err != nil {
+			if errors.Is(err, driver.ErrReleaseNotFound) {
+				continue
+			}
+
+			// The releases are usually stored using a single driver, so don't fail if the other one is not accessible
+			if apierrors.IsForbidden(err) {
+				plugin.Logger(ctx).Warn("listHelmReleaseRevisions", "storage_driver", drv.Name(), "namespace", namespace, "error", err)
+				continue
+			}
+			return nil, err
+		}
+
+		for _, item := range items {
+			// Skip the releases of the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, item.Namespace) {
+				continue
+			}
+			releases = append(releases, HelmRelease{*item, strings.ToLower(drv.Name())})
+		}
+	}
+
+	// Sort the revisions to return the releases in a consistent order
+	sort.SliceStable(releases, func(i, j int) bool {
+		if releases[i].Namespace != releases[j].Namespace {
+			return releases[i].Namespace < releases[j].Namespace
+		}
+		if releases[i].Name != releases[j].Name {
+			return releases[i].Name < releases[j].Name
+		}
+		return releases[i].Version < releases[j].Version
+	})
+
+	return releases, nil
+}
+
+// filterLatestHelmReleases returns the latest revision of each release, as done by `helm list`
+func filterLatestHelmReleases(releases []HelmRelease) []HelmRelease {
+	var latest []HelmRelease
+	index := map[string]int{}
+
+	for _, r := range releases {
+		key := path.Join(r.Namespace, r.Name)
+		if i, ok := index[key]; ok {
+			if latest[i].Version < r.Version {
+				latest[i] = r
+			}
+			continue
+		}
+		index[key] = len(latest)
+		latest = append(latest, r)
+	}
+
+	return latest
 }
 
 // getRows takes the chart values values as input and returns all the keys and values in tree structure
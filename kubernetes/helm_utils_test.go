@@ -0,0 +1,57 @@
+package kubernetes
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestGetHelmWatchPaths(t *testing.T) {
+	config := kubernetesConfig{
+		HelmRenderedCharts: map[string]chartConfig{
+			"my-app-dev": {
+				ChartPath:       "/charts/my-app",
+				ValuesFilePaths: []string{"/values/dev.yaml"},
+			},
+			"my-app-prod": {
+				ChartPath:       "/charts/my-app/",
+				ValuesFilePaths: []string{"/values/prod.yaml", "/values/common.yaml"},
+			},
+			"other": {
+				ChartPath:       "/charts/other",
+				ValuesFilePaths: []string{"/values/common.yaml"},
+			},
+		},
+	}
+
+	// The packaged charts are watched as is, whether they already exist or not
+	archive := filepath.Join(t.TempDir(), "packaged")
+	if err := os.WriteFile(archive, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	config.HelmRenderedCharts["packaged"] = chartConfig{ChartPath: archive}
+	config.HelmRenderedCharts["missing-archive"] = chartConfig{ChartPath: "/charts/missing-1.0.0.tgz"}
+
+	expected := []string{
+		"/charts/missing-1.0.0.tgz",
+		"/charts/my-app/**/*",
+		"/charts/other/**/*",
+		"/values/common.yaml",
+		"/values/dev.yaml",
+		"/values/prod.yaml",
+	}
+
+	expected = append(expected, archive)
+	sort.Strings(expected)
+
+	got := getHelmWatchPaths(config)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("getHelmWatchPaths() = %v, expected %v", got, expected)
+	}
+
+	if got := getHelmWatchPaths(kubernetesConfig{}); len(got) != 0 {
+		t.Errorf("expected no watch paths without configured charts, got %v", got)
+	}
+}
@@ -0,0 +1,409 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+// Utils functions for the values.schema.json files defined in the charts
+
+// helmValueSchemaArrayItemKey is used as the key of an array item in the flattened schema, e.g. ["ingress", "hosts", "*", "host"]
+const helmValueSchemaArrayItemKey = "*"
+
+type helmValueSchemaProperty struct {
+	ChartName   string
+	Path        string
+	Key         []string
+	Type        string
+	Format      string
+	Required    bool
+	Default     interface{}
+	Enum        []interface{}
+	Description string
+}
+
+type helmValueSchemaViolation struct {
+	ChartName   string
+	ChartPath   string
+	SourceType  string
+	Key         []string
+	ErrorType   string
+	Message     string
+	Value       interface{}
+	Path        string
+	StartLine   int
+	StartColumn int
+}
+
+// getHelmChartSchemaPath returns the path of the values.schema.json file of the given chart or subchart
+func getHelmChartSchemaPath(root *chart.Chart, rootPath string, c *chart.Chart) string {
+	return path.Join(getHelmSubchartPath(root, rootPath, c), chartutil.SchemafileName)
+}
+
+// getHelmValueSchemaProperties returns the flattened schema properties of the given chart along with all of its subcharts.
+// The keys of the subchart properties are prefixed with the subchart name, since the subchart values are nested under it.
+func getHelmValueSchemaProperties(root *chart.Chart, rootPath string, c *chart.Chart, prefix []string) ([]helmValueSchemaProperty, error) {
+	var properties []helmValueSchemaProperty
+
+	if len(c.Schema) > 0 {
+		var schema map[string]interface{}
+		if err := json.Unmarshal(c.Schema, &schema); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", getHelmChartSchemaPath(root, rootPath, c), err)
+		}
+
+		flattenHelmValueSchema(schema, schema, prefix, false, nil, func(key []string, node map[string]interface{}, required bool) {
+			format, _ := node["format"].(string)
+			description, _ := node["description"].(string)
+			properties = append(properties, helmValueSchemaProperty{
+				ChartName:   c.Name(),
+				Path:        getHelmChartSchemaPath(root, rootPath, c),
+				Key:         key,
+				Type:        getHelmValueSchemaType(node),
+				Format:      format,
+				Required:    required,
+				Default:     node["default"],
+				Enum:        toInterfaceSlice(node["enum"]),
+				Description: description,
+			})
+		})
+	}
+
+	for _, subchart := range c.Dependencies() {
+		subchartProperties, err := getHelmValueSchemaProperties(root, rootPath, subchart, appendKey(prefix, subchart.Name()))
+		if err != nil {
+			return nil, err
+		}
+		properties = append(properties, subchartProperties...)
+	}
+
+	return properties, nil
+}
+
+// flattenHelmValueSchema walks the given JSON schema node and calls the visit function for every nested property.
+// Local references (e.g. "#/definitions/image") are resolved against the root schema; recursive references are
+// only followed once per branch.
+func flattenHelmValueSchema(root map[string]interface{}, node map[string]interface{}, key []string, required bool, visitedRefs []string, visit func([]string, map[string]interface{}, bool)) {
+	if ref, ok := node["$ref"].(string); ok {
+		if slices.Contains(visitedRefs, ref) {
+			return
+		}
+		if resolved := resolveHelmValueSchemaRef(root, ref); resolved != nil {
+			visitedRefs = append(slices.Clone(visitedRefs), ref)
+
+			// Keep the local annotations, e.g. description, over the referenced ones
+			merged := map[string]interface{}{}
+			for k, v := range resolved {
+				merged[k] = v
+			}
+			for k, v := range node {
+				if k != "$ref" {
+					merged[k] = v
+				}
+			}
+			node = merged
+		}
+	}
+
+	if len(key) > 0 {
+		visit(key, node, required)
+	}
+
+	var requiredProperties []string
+	for _, r := range toInterfaceSlice(node["required"]) {
+		if s, ok := r.(string); ok {
+			requiredProperties = append(requiredProperties, s)
+		}
+	}
+
+	if properties, ok := node["properties"].(map[string]interface{}); ok {
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if property, ok := properties[name].(map[string]interface{}); ok {
+				flattenHelmValueSchema(root, property, appendKey(key, name), slices.Contains(requiredProperties, name), visitedRefs, visit)
+			}
+		}
+	}
+
+	if items, ok := node["items"].(map[string]interface{}); ok {
+		flattenHelmValueSchema(root, items, appendKey(key, helmValueSchemaArrayItemKey), false, visitedRefs, visit)
+	}
+}
+
+// resolveHelmValueSchemaRef resolves a local JSON pointer reference, e.g. "#/definitions/image" or "#/$defs/image"
+func resolveHelmValueSchemaRef(root map[string]interface{}, ref string) map[string]interface{} {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+
+	current := root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		next, ok := current[token].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = next
+	}
+
+	return current
+}
+
+// getHelmValueSchemaType returns the type of the schema node. Multiple types are joined using a comma, e.g. "string,null".
+func getHelmValueSchemaType(node map[string]interface{}) string {
+	switch t := node["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		types := []string{}
+		for _, i := range t {
+			types = append(types, fmt.Sprint(i))
+		}
+		return strings.Join(types, ",")
+	}
+	return ""
+}
+
+// getHelmValueSchemaViolations validates the values of every configured chart render against the schema of the chart and its subcharts
+func getHelmValueSchemaViolations(ctx context.Context, d *plugin.QueryData) ([]helmValueSchemaViolation, error) {
+	charts, err := getUniqueHelmCharts(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	kubernetesConfig := GetConfig(d.Connection)
+
+	// Sort the config keys to return the violations in a consistent order
+	configKeys := make([]string, 0, len(kubernetesConfig.HelmRenderedCharts))
+	for name := range kubernetesConfig.HelmRenderedCharts {
+		configKeys = append(configKeys, name)
+	}
+	sort.Strings(configKeys)
+
+	var violations []helmValueSchemaViolation
+	for _, parsedChart := range charts {
+		for _, name := range configKeys {
+			c := kubernetesConfig.HelmRenderedCharts[name]
+			if c.ChartPath != parsedChart.Path {
+				continue
+			}
+
+			chrt, vals, err := loadHelmChartForConfig(c)
+			if err != nil {
+				plugin.Logger(ctx).Error("getHelmValueSchemaViolations", "load_chart_error", err, "config", name)
+				return nil, err
+			}
+
+			coalescedValues, err := chartutil.CoalesceValues(chrt, vals)
+			if err != nil {
+				return nil, err
+			}
+
+			valueRows, err := getHelmValueFileRows(chrt, c.ChartPath, c.ValuesFilePaths)
+			if err != nil {
+				return nil, err
+			}
+
+			configViolations, err := validateHelmValuesAgainstSchema(chrt, coalescedValues, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, v := range configViolations {
+				v.ChartPath = c.ChartPath
+				v.SourceType = fmt.Sprintf("helm_rendered:%s", name)
+				if row := findHelmValueRowByKey(valueRows, v.Key); row != nil {
+					v.Path = row.Path
+					v.StartLine = row.StartLine
+					v.StartColumn = row.StartColumn
+				}
+				violations = append(violations, v)
+			}
+		}
+	}
+
+	return violations, nil
+}
+
+// validateHelmValuesAgainstSchema checks the values against the schema of the chart, and recursively against the schema of its subcharts
+func validateHelmValuesAgainstSchema(c *chart.Chart, vals map[string]interface{}, prefix []string) (violations []helmValueSchemaViolation, err error) {
+	if len(c.Schema) > 0 {
+		schemaViolations, err := validateHelmValuesAgainstSingleSchema(vals, c.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", c.Name(), err)
+		}
+		for _, v := range schemaViolations {
+			v.ChartName = c.Name()
+			v.Key = append(slices.Clone(prefix), v.Key...)
+			violations = append(violations, v)
+		}
+	}
+
+	for _, subchart := range c.Dependencies() {
+		subchartValues, _ := vals[subchart.Name()].(map[string]interface{})
+		subchartViolations, err := validateHelmValuesAgainstSchema(subchart, subchartValues, appendKey(prefix, subchart.Name()))
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, subchartViolations...)
+	}
+
+	return violations, nil
+}
+
+// validateHelmValuesAgainstSingleSchema returns the violations of the values against the given schema
+func validateHelmValuesAgainstSingleSchema(vals map[string]interface{}, schemaJSON []byte) (violations []helmValueSchemaViolation, err error) {
+	// The validator panics on some malformed schemas
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unable to validate schema: %s", r)
+		}
+	}()
+
+	if vals == nil {
+		vals = map[string]interface{}{}
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schemaJSON), gojsonschema.NewGoLoader(vals))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range result.Errors() {
+		// The context is in format of (root).image.tag. Use a separator that can't be part of the key
+		// to split it, since the keys can contain dots.
+		key := strings.Split(e.Context().String("\x00"), "\x00")[1:]
+
+		// For the missing properties, point at the missing property instead of its parent
+		if e.Type() == "required" {
+			if property, ok := e.Details()["property"].(string); ok {
+				key = append(key, property)
+			}
+		}
+
+		violations = append(violations, helmValueSchemaViolation{
+			Key:       key,
+			ErrorType: e.Type(),
+			Message:   e.Description(),
+			Value:     e.Value(),
+		})
+	}
+
+	return violations, nil
+}
+
+// getHelmValueFileRows returns the rows of the default values files of the chart and its subcharts, followed by the rows of the given value override files
+func getHelmValueFileRows(c *chart.Chart, chartPath string, valueFilePaths []string) (Rows, error) {
+	var rows Rows
+
+	var addDefaultValues func(*chart.Chart, []string) error
+	addDefaultValues = func(current *chart.Chart, prefix []string) error {
+		for _, f := range current.Raw {
+			if f.Name != chartutil.ValuesfileName {
+				continue
+			}
+			fileRows, err := parseValueFileRows(getHelmChartValuesPath(c, chartPath, current), f.Data, prefix)
+			if err != nil {
+				return err
+			}
+			rows = append(rows, fileRows...)
+		}
+		for _, subchart := range current.Dependencies() {
+			if err := addDefaultValues(subchart, appendKey(prefix, subchart.Name())); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := addDefaultValues(c, nil); err != nil {
+		return nil, err
+	}
+
+	for _, p := range valueFilePaths {
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		fileRows, err := parseValueFileRows(p, content, nil)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, fileRows...)
+	}
+
+	return rows, nil
+}
+
+// getHelmChartValuesPath returns the path of the values.yaml file of the given chart or subchart
+func getHelmChartValuesPath(root *chart.Chart, rootPath string, c *chart.Chart) string {
+	return path.Join(getHelmSubchartPath(root, rootPath, c), chartutil.ValuesfileName)
+}
+
+// parseValueFileRows parses the content of a values file, keeping the original line and column of every value
+func parseValueFileRows(filePath string, content []byte, prefix []string) (Rows, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", filePath, err)
+	}
+
+	var rows Rows
+	treeToList(&root, prefix, &rows, nil, nil, nil)
+	for i := range rows {
+		rows[i].Path = filePath
+	}
+
+	return rows, nil
+}
+
+// findHelmValueRowByKey returns the row defining the given key. If the key is not defined in any of the files,
+// e.g. a missing required property, the row sharing the longest key prefix is returned instead.
+// The later files take precedence, since they override the values of the earlier ones.
+func findHelmValueRowByKey(rows Rows, key []string) *Row {
+	var match *Row
+	matchLength := 0
+
+	for i := len(rows) - 1; i >= 0; i-- {
+		length := 0
+		for length < len(key) && length < len(rows[i].Key) && rows[i].Key[length] == key[length] {
+			length++
+		}
+		if length == len(key) && length == len(rows[i].Key) {
+			return &rows[i]
+		}
+		if length > matchLength {
+			match = &rows[i]
+			matchLength = length
+		}
+	}
+
+	return match
+}
+
+// appendKey returns a new key with the given element appended, without modifying the original key
+func appendKey(key []string, element string) []string {
+	return append(slices.Clone(key), element)
+}
+
+func toInterfaceSlice(v interface{}) []interface{} {
+	if s, ok := v.([]interface{}); ok {
+		return s
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+const testValuesSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["image"],
+  "definitions": {
+    "port": {"type": "integer", "minimum": 1, "description": "A port number."}
+  },
+  "properties": {
+    "image": {
+      "type": "object",
+      "required": ["tag"],
+      "properties": {
+        "repository": {"type": "string", "default": "nginx"},
+        "tag": {"type": "string"}
+      }
+    },
+    "service": {
+      "type": "object",
+      "properties": {
+        "ports": {"type": "array", "items": {"$ref": "#/definitions/port"}}
+      }
+    }
+  }
+}`
+
+func TestGetHelmValueSchemaProperties(t *testing.T) {
+	c := &chart.Chart{Metadata: &chart.Metadata{Name: "app"}, Schema: []byte(testValuesSchema)}
+
+	properties, err := getHelmValueSchemaProperties(c, "/charts/app", c, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got [][]string
+	for _, p := range properties {
+		got = append(got, p.Key)
+	}
+	expected := [][]string{
+		{"image"},
+		{"image", "repository"},
+		{"image", "tag"},
+		{"service"},
+		{"service", "ports"},
+		{"service", "ports", "*"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("keys mismatch\n  got:      %v\n  expected: %v", got, expected)
+	}
+
+	if !properties[0].Required || properties[1].Required || !properties[2].Required {
+		t.Errorf("required flags mismatch: %+v", properties[:3])
+	}
+	if properties[1].Default != "nginx" {
+		t.Errorf("expected default nginx, got %v", properties[1].Default)
+	}
+	if properties[5].Type != "integer" || properties[5].Description != "A port number." {
+		t.Errorf("expected the $ref to be resolved, got %+v", properties[5])
+	}
+	if properties[0].Path != "/charts/app/values.schema.json" {
+		t.Errorf("unexpected schema path %s", properties[0].Path)
+	}
+}
+
+func TestValidateHelmValuesAgainstSchema(t *testing.T) {
+	c := &chart.Chart{Metadata: &chart.Metadata{Name: "app"}, Schema: []byte(testValuesSchema)}
+
+	violations, err := validateHelmValuesAgainstSchema(c, map[string]interface{}{
+		"image":   map[string]interface{}{"repository": "nginx"},
+		"service": map[string]interface{}{"ports": []interface{}{0}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[string][]string{}
+	for _, v := range violations {
+		got[v.ErrorType] = v.Key
+	}
+	expected := map[string][]string{
+		"required":   {"image", "tag"},
+		"number_gte": {"service", "ports", "0"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("violations mismatch\n  got:      %v\n  expected: %v", got, expected)
+	}
+}
+
+func TestFindHelmValueRowByKey(t *testing.T) {
+	defaults, err := parseValueFileRows("values.yaml", []byte("image:\n  repository: nginx\n  tag: latest\n"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	overrides, err := parseValueFileRows("prod.yaml", []byte("replicas: 2\nimage:\n  tag: 1.25\n"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows := append(defaults, overrides...)
+
+	tests := []struct {
+		key      []string
+		path     string
+		line     int
+		notFound bool
+	}{
+		{key: []string{"image", "tag"}, path: "prod.yaml", line: 3},
+		{key: []string{"image", "repository"}, path: "values.yaml", line: 2},
+		{key: []string{"image", "pullPolicy"}, path: "prod.yaml", line: 3},
+		{key: []string{"resources"}, notFound: true},
+	}
+
+	for _, tc := range tests {
+		row := findHelmValueRowByKey(rows, tc.key)
+		if tc.notFound {
+			if row != nil {
+				t.Errorf("%v: expected no row, got %+v", tc.key, row)
+			}
+			continue
+		}
+		if row == nil || row.Path != tc.path || row.StartLine != tc.line {
+			t.Errorf("%v: expected %s:%d, got %+v", tc.key, tc.path, tc.line, row)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Utils functions for the kubernetes_kubeconfig_* tables, which read the contents of the kubeconfig files of the connection
+
+// kubeconfigSecretAuthProviderKeys are the keys of the auth provider config which hold secrets
+var kubeconfigSecretAuthProviderKeys = []string{"access-token", "client-secret", "id-token", "refresh-token"}
+
+// kubeconfigFile is the contents of a kubeconfig file of the connection
+type kubeconfigFile struct {
+	Path   string
+	Config *clientcmdapi.Config
+}
+
+// getKubeconfigFiles returns the contents of each kubeconfig file of the connection, in the order the files are merged by the clients.
+// The files are not merged, so that the entries defined in several files, e.g. a context named default, are all returned.
+// The inline and explicit configs are returned as a single file with no path. Returns nil if the deployed source is disabled.
+func getKubeconfigFiles(ctx context.Context, d *plugin.QueryData) ([]kubeconfigFile, error) {
+	kubeconfig, err := getK8Config(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	if kubeconfig == nil {
+		return nil, nil
+	}
+
+	deferred, ok := kubeconfig.(*clientcmd.DeferredLoadingClientConfig)
+	if !ok {
+		rawConfig, err := kubeconfig.RawConfig()
+		if err != nil {
+			return nil, err
+		}
+		return []kubeconfigFile{{Config: &rawConfig}}, nil
+	}
+
+	return loadKubeconfigFiles(deferred.ConfigAccess().GetLoadingPrecedence())
+}
+
+// loadKubeconfigFiles loads the given kubeconfig files separately. The files which do not exist are skipped, as when they are merged.
+func loadKubeconfigFiles(paths []string) ([]kubeconfigFile, error) {
+	var files []kubeconfigFile
+	for _, path := range paths {
+		config, err := clientcmd.LoadFromFile(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("error loading kubeconfig file %s: %w", path, err)
+		}
+
+		// The relative paths of the certificates and the token files are relative to the kubeconfig file
+		if err := clientcmd.ResolveLocalPaths(config); err != nil {
+			return nil, err
+		}
+		files = append(files, kubeconfigFile{Path: path, Config: config})
+	}
+	return files, nil
+}
+
+// sortedKubeconfigNames returns the names of the given kubeconfig entries, e.g. the contexts, in alphabetical order
+func sortedKubeconfigNames[T any](entries map[string]T) []string {
+	return slices.Sorted(maps.Keys(entries))
+}
+
+// getKubeconfigAuthType returns the method used to authenticate with the given kubeconfig user, in the same way as
+// the auth_method of kubernetes_connection_info, e.g. exec, token or the name of the auth provider, e.g. oidc
+func getKubeconfigAuthType(authInfo *clientcmdapi.AuthInfo) string {
+	return getAuthMethod(&rest.Config{
+		ExecProvider:    authInfo.Exec,
+		AuthProvider:    authInfo.AuthProvider,
+		BearerToken:     authInfo.Token,
+		BearerTokenFile: authInfo.TokenFile,
+		Username:        authInfo.Username,
+		TLSClientConfig: rest.TLSClientConfig{
+			CertData: authInfo.ClientCertificateData,
+			CertFile: authInfo.ClientCertificate,
+		},
+	})
+}
+
+// getClientCertificateNotAfter returns the expiry of the client certificate of the given kubeconfig user, if any
+func getClientCertificateNotAfter(authInfo *clientcmdapi.AuthInfo) (*time.Time, error) {
+	data := authInfo.ClientCertificateData
+	if len(data) == 0 {
+		if authInfo.ClientCertificate == "" {
+			return nil, nil
+		}
+		var err error
+		data, err = os.ReadFile(authInfo.ClientCertificate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The first certificate is the client certificate, the others being the intermediate certificates, if any
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return &certificate.NotAfter, nil
+	}
+	return nil, errors.New("no certificate found in the client certificate data")
+}
+
+// redactAuthProviderConfig returns the config of an auth provider, with the tokens and client secrets redacted
+func redactAuthProviderConfig(config map[string]string) map[string]string {
+	redacted := map[string]string{}
+	for key, value := range config {
+		if slices.Contains(kubeconfigSecretAuthProviderKeys, key) && value != "" {
+			value = redactedValue
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
+
+// redactExecEnv returns the environment variables set for an exec plugin, with their values redacted since they often hold secrets
+func redactExecEnv(env []clientcmdapi.ExecEnvVar) map[string]string {
+	redacted := map[string]string{}
+	for _, envVar := range env {
+		redacted[envVar.Name] = redactedValue
+	}
+	return redacted
+}
@@ -0,0 +1,120 @@
+package kubernetes
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestGetKubeconfigAuthType(t *testing.T) {
+	tests := []struct {
+		name     string
+		authInfo *clientcmdapi.AuthInfo
+		expected string
+	}{
+		{name: "exec", authInfo: &clientcmdapi.AuthInfo{Exec: &clientcmdapi.ExecConfig{Command: "kubelogin"}}, expected: "exec"},
+		{name: "oidc", authInfo: &clientcmdapi.AuthInfo{AuthProvider: &clientcmdapi.AuthProviderConfig{Name: "oidc"}}, expected: "oidc"},
+		{name: "token", authInfo: &clientcmdapi.AuthInfo{Token: "token"}, expected: "token"},
+		{name: "token file", authInfo: &clientcmdapi.AuthInfo{TokenFile: "/tmp/token"}, expected: "token_file"},
+		{name: "client certificate", authInfo: &clientcmdapi.AuthInfo{ClientCertificateData: []byte("cert"), ClientKeyData: []byte("key")}, expected: "client_certificate"},
+		{name: "basic", authInfo: &clientcmdapi.AuthInfo{Username: "admin"}, expected: "basic"},
+		{name: "none", authInfo: &clientcmdapi.AuthInfo{}, expected: "none"},
+	}
+	for _, tt := range tests {
+		if got := getKubeconfigAuthType(tt.authInfo); got != tt.expected {
+			t.Errorf("%s: getKubeconfigAuthType() = %q, expected %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestGetClientCertificateNotAfter(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "admin"}, NotBefore: notAfter.AddDate(-1, 0, 0), NotAfter: notAfter}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	got, err := getClientCertificateNotAfter(&clientcmdapi.AuthInfo{ClientCertificateData: data})
+	if err != nil || got == nil || !got.Equal(notAfter) {
+		t.Errorf("getClientCertificateNotAfter() = %v, %v; expected %v", got, err, notAfter)
+	}
+
+	if got, err := getClientCertificateNotAfter(&clientcmdapi.AuthInfo{Token: "token"}); got != nil || err != nil {
+		t.Errorf("getClientCertificateNotAfter(no certificate) = %v, %v; expected nil", got, err)
+	}
+	if _, err := getClientCertificateNotAfter(&clientcmdapi.AuthInfo{ClientCertificateData: []byte("not a certificate")}); err == nil {
+		t.Errorf("getClientCertificateNotAfter(invalid) expected an error")
+	}
+}
+
+func TestRedactAuthProviderConfig(t *testing.T) {
+	config := map[string]string{"client-id": "steampipe", "client-secret": "secret", "id-token": "token", "idp-issuer-url": "https://issuer.example.com", "refresh-token": ""}
+	expected := map[string]string{"client-id": "steampipe", "client-secret": redactedValue, "id-token": redactedValue, "idp-issuer-url": "https://issuer.example.com", "refresh-token": ""}
+	if got := redactAuthProviderConfig(config); !reflect.DeepEqual(got, expected) {
+		t.Errorf("redactAuthProviderConfig() = %v, expected %v", got, expected)
+	}
+}
+
+func TestLoadKubeconfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first")
+	second := filepath.Join(dir, "second")
+	if err := os.WriteFile(first, []byte(inlineKubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte(`apiVersion: v1
+kind: Config
+contexts:
+  - name: my-context
+    context:
+      cluster: staging
+      user: my-user
+users:
+  - name: my-user
+    user:
+      client-certificate: certs/client.crt
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The missing files are skipped, and the entries defined in several files are kept separately
+	files, err := loadKubeconfigFiles([]string{first, filepath.Join(dir, "missing"), second})
+	if err != nil {
+		t.Fatalf("loadKubeconfigFiles() error = %v", err)
+	}
+	if len(files) != 2 || files[0].Path != first || files[1].Path != second {
+		t.Fatalf("loadKubeconfigFiles() = %+v, expected the first and second files", files)
+	}
+	if cluster := files[1].Config.Contexts["my-context"].Cluster; cluster != "staging" {
+		t.Errorf("context of the second file = %q, expected %q", cluster, "staging")
+	}
+
+	// The relative paths are relative to the kubeconfig file
+	if certificate := files[1].Config.AuthInfos["my-user"].ClientCertificate; certificate != filepath.Join(dir, "certs", "client.crt") {
+		t.Errorf("client certificate = %q, expected it relative to the kubeconfig file", certificate)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "invalid"), []byte("not: [a kubeconfig"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadKubeconfigFiles([]string{filepath.Join(dir, "invalid")}); err == nil {
+		t.Errorf("loadKubeconfigFiles(invalid) expected an error")
+	}
+}
@@ -0,0 +1,357 @@
+package kubernetes
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	goYaml "gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Utils functions to locate the objects and their fields in the manifest files
+
+type manifestDocument struct {
+	Object    *unstructured.Unstructured
+	Node      *goYaml.Node
+	StartLine int
+	EndLine   int
+}
+
+// flowCollectionEnd matches the lines closing flow collections only, e.g. the `},` line of a JSON object in an array
+var flowCollectionEnd = regexp.MustCompile(`^[\s\]\},]*$`)
+
+// decodeManifestDocuments decodes the objects of a manifest file, which can be a stream of YAML documents or a JSON file.
+// The lines of the objects are taken from their YAML node, so they are exact regardless of the comments, the line endings
+// or the formatting of the file.
+func decodeManifestDocuments(content []byte) ([]manifestDocument, error) {
+	lines := strings.Split(string(content), "\n")
+
+	var documents []manifestDocument
+	decoder := goYaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var document goYaml.Node
+		if err := decoder.Decode(&document); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		// Skip the empty documents
+		if document.Kind != goYaml.DocumentNode || len(document.Content) == 0 {
+			continue
+		}
+
+		// Lists, e.g. `kind: List`, and JSON arrays are expanded into their items
+		for _, item := range manifestDocumentItems(document.Content[0]) {
+			node := item.Node
+
+			// Skip if no kind defined, before decoding the object which would fail without a kind
+			if item.Kind == "" {
+				continue
+			}
+
+			// The node is encoded back, so that the objects are decoded in the same way as the other sources
+			content, err := goYaml.Marshal(node)
+			if err != nil {
+				return nil, err
+			}
+
+			// The items of the typed lists may have no kind, which the decoder of the objects would reject
+			obj := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal(content, &obj.Object); err != nil {
+				return nil, err
+			}
+			if obj.GetKind() == "" {
+				obj.SetKind(item.Kind)
+			}
+			if obj.GetAPIVersion() == "" && item.APIVersion != "" {
+				obj.SetAPIVersion(item.APIVersion)
+			}
+
+			documents = append(documents, manifestDocument{
+				Object:    obj,
+				Node:      node,
+				StartLine: node.Line,
+				EndLine:   yamlNodeEndLine(node, lines),
+			})
+		}
+	}
+
+	return documents, nil
+}
+
+// decodeManifestDocumentsWithoutPositions decodes the objects of a manifest file which cannot be parsed as a YAML node tree
+func decodeManifestDocumentsWithoutPositions(content []byte) ([]manifestDocument, error) {
+	objects, err := decodeSnapshotFile(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []manifestDocument
+	for _, obj := range objects {
+		for _, item := range expandListItems(obj) {
+			documents = append(documents, manifestDocument{Object: item})
+		}
+	}
+
+	return documents, nil
+}
+
+type manifestDocumentItem struct {
+	Node *goYaml.Node
+	// The kind and apiVersion of the item, inherited from the typed lists if missing, e.g. Pod for a PodList
+	Kind       string
+	APIVersion string
+}
+
+// manifestDocumentItems returns the objects of a manifest document.
+// The items of the lists, e.g. `kind: List` or `kind: PodList`, and of the JSON arrays are returned as separate objects.
+// The items with no kind defined, which cannot be inherited from a typed list, are skipped.
+func manifestDocumentItems(node *goYaml.Node) []manifestDocumentItem {
+	var items []*goYaml.Node
+	var itemKind, itemAPIVersion string
+	switch node.Kind {
+	case goYaml.SequenceNode:
+		items = node.Content
+	case goYaml.MappingNode:
+		kind, _ := yamlMappingValue(node, "kind")
+		list, _ := yamlMappingValue(node, "items")
+		if kind == nil || !strings.HasSuffix(kind.Value, "List") || list == nil || list.Kind != goYaml.SequenceNode {
+			return []manifestDocumentItem{{Node: node, Kind: yamlMappingScalar(node, "kind"), APIVersion: yamlMappingScalar(node, "apiVersion")}}
+		}
+		items = list.Content
+		itemKind = strings.TrimSuffix(kind.Value, "List")
+		itemAPIVersion = yamlMappingScalar(node, "apiVersion")
+	default:
+		return nil
+	}
+
+	var objects []manifestDocumentItem
+	for _, item := range items {
+		if item.Kind != goYaml.MappingNode {
+			continue
+		}
+
+		object := manifestDocumentItem{
+			Node:       item,
+			Kind:       cmp.Or(yamlMappingScalar(item, "kind"), itemKind),
+			APIVersion: cmp.Or(yamlMappingScalar(item, "apiVersion"), itemAPIVersion),
+		}
+		if object.Kind == "" {
+			continue
+		}
+		objects = append(objects, object)
+	}
+	return objects
+}
+
+// yamlMappingValue returns the value and the key nodes of the given key of a mapping node
+func yamlMappingValue(node *goYaml.Node, key string) (*goYaml.Node, *goYaml.Node) {
+	if node.Kind != goYaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1], node.Content[i]
+		}
+	}
+	return nil, nil
+}
+
+// yamlMappingScalar returns the value of the given key of a mapping node if it is a scalar, e.g. the kind of an object
+func yamlMappingScalar(node *goYaml.Node, key string) string {
+	value, _ := yamlMappingValue(node, key)
+	if value == nil || value.Kind != goYaml.ScalarNode {
+		return ""
+	}
+	return value.Value
+}
+
+// yamlNodeEndLine returns the last line of the given node, based on its last descendant.
+// The lines of the file are used to include the closing brackets of the flow collections, e.g. in the JSON files.
+func yamlNodeEndLine(node *goYaml.Node, lines []string) int {
+	// Count the flow collections closed after the last descendant
+	openFlows := 0
+	last := node
+	for {
+		if (last.Kind == goYaml.MappingNode || last.Kind == goYaml.SequenceNode) && last.Style&goYaml.FlowStyle != 0 {
+			openFlows++
+		}
+		if len(last.Content) == 0 {
+			break
+		}
+		last = last.Content[len(last.Content)-1]
+	}
+
+	end := last.Line
+	if last.Kind == goYaml.ScalarNode && last.Style&(goYaml.LiteralStyle|goYaml.FoldedStyle) != 0 && last.Value != "" {
+		end += strings.Count(strings.TrimSuffix(last.Value, "\n"), "\n") + 1
+	}
+
+	// The brackets closed on the last line of the value, e.g. `"name": "web"}`
+	if end >= 1 && end <= len(lines) && end == last.Line && last.Column >= 1 && last.Column <= len(lines[end-1]) {
+		openFlows -= strings.Count(lines[end-1][last.Column-1:], "]") + strings.Count(lines[end-1][last.Column-1:], "}")
+	}
+
+	// The brackets closed on the following lines, e.g. `},`
+	for openFlows > 0 && end < len(lines) && flowCollectionEnd.MatchString(lines[end]) && strings.TrimSpace(lines[end]) != "" {
+		openFlows -= strings.Count(lines[end], "]") + strings.Count(lines[end], "}")
+		end++
+	}
+
+	return end
+}
+
+type manifestField struct {
+	Keys      []string
+	FieldPath string
+	Key       *goYaml.Node
+	Value     *goYaml.Node
+	Line      int
+	Column    int
+}
+
+// getManifestFields returns all the fields of the given object node, parents first.
+// The position of the fields in a mapping is the position of their key, e.g. the line of `privileged: true`.
+func getManifestFields(node *goYaml.Node, keys []string, fieldPath string) []manifestField {
+	var fields []manifestField
+	switch node.Kind {
+	case goYaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field := newManifestField(keys, key.Value, joinFieldPath(fieldPath, key.Value), key, value)
+			fields = append(fields, field)
+			fields = append(fields, getManifestFields(value, field.Keys, field.FieldPath)...)
+		}
+	case goYaml.SequenceNode:
+		for i, value := range node.Content {
+			field := newManifestField(keys, strconv.Itoa(i), fmt.Sprintf("%s[%d]", fieldPath, i), nil, value)
+			fields = append(fields, field)
+			fields = append(fields, getManifestFields(value, field.Keys, field.FieldPath)...)
+		}
+	}
+	return fields
+}
+
+func newManifestField(parentKeys []string, key string, fieldPath string, keyNode *goYaml.Node, value *goYaml.Node) manifestField {
+	keys := make([]string, len(parentKeys), len(parentKeys)+1)
+	copy(keys, parentKeys)
+
+	field := manifestField{
+		Keys:      append(keys, key),
+		FieldPath: fieldPath,
+		Key:       keyNode,
+		Value:     value,
+		Line:      value.Line,
+		Column:    value.Column,
+	}
+	if keyNode != nil {
+		field.Line = keyNode.Line
+		field.Column = keyNode.Column
+	}
+	return field
+}
+
+// findManifestField returns the field of the given object node at the given field path,
+// e.g. `spec.containers[0].securityContext.privileged` or `metadata.labels["app.kubernetes.io/name"]`.
+func findManifestField(node *goYaml.Node, fieldPath string) (manifestField, bool) {
+	segments, err := parseFieldPath(fieldPath)
+	if err != nil || len(segments) == 0 {
+		return manifestField{}, false
+	}
+
+	var field manifestField
+	current := node
+	path := ""
+	for _, segment := range segments {
+		if segment.IsIndex {
+			if current.Kind != goYaml.SequenceNode || segment.Index < 0 || segment.Index >= len(current.Content) {
+				return manifestField{}, false
+			}
+			path = fmt.Sprintf("%s[%d]", path, segment.Index)
+			field = newManifestField(field.Keys, strconv.Itoa(segment.Index), path, nil, current.Content[segment.Index])
+		} else {
+			value, key := yamlMappingValue(current, segment.Key)
+			if value == nil {
+				return manifestField{}, false
+			}
+			path = joinFieldPath(path, segment.Key)
+			field = newManifestField(field.Keys, segment.Key, path, key, value)
+		}
+		current = field.Value
+	}
+
+	return field, true
+}
+
+// joinFieldPath appends the given key to the field path. The keys which cannot be used as is, e.g. the keys containing
+// dots such as `app.kubernetes.io/name`, are quoted between brackets.
+func joinFieldPath(fieldPath string, key string) string {
+	if key == "" || strings.ContainsAny(key, `.[]"`) {
+		return fieldPath + "[" + strconv.Quote(key) + "]"
+	}
+	if fieldPath == "" {
+		return key
+	}
+	return fieldPath + "." + key
+}
+
+type fieldPathSegment struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+// parseFieldPath parses a field path built by joinFieldPath into its segments
+func parseFieldPath(fieldPath string) ([]fieldPathSegment, error) {
+	var segments []fieldPathSegment
+	for i := 0; i < len(fieldPath); {
+		switch fieldPath[i] {
+		case '.':
+			i++
+		case '[':
+			end := strings.IndexByte(fieldPath[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid field path %q: unclosed bracket", fieldPath)
+			}
+
+			// Quoted keys may contain brackets, so find the closing quote first
+			if fieldPath[i+1] == '"' {
+				quoted, err := strconv.QuotedPrefix(fieldPath[i+1:])
+				if err != nil {
+					return nil, fmt.Errorf("invalid field path %q: %v", fieldPath, err)
+				}
+				key, _ := strconv.Unquote(quoted)
+				if !strings.HasPrefix(fieldPath[i+1+len(quoted):], "]") {
+					return nil, fmt.Errorf("invalid field path %q: unclosed bracket", fieldPath)
+				}
+				segments = append(segments, fieldPathSegment{Key: key})
+				i += len(quoted) + 2
+				continue
+			}
+
+			index, err := strconv.Atoi(fieldPath[i+1 : i+end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid field path %q: invalid index %q", fieldPath, fieldPath[i+1:i+end])
+			}
+			segments = append(segments, fieldPathSegment{Index: index, IsIndex: true})
+			i += end + 1
+		default:
+			end := strings.IndexAny(fieldPath[i:], ".[")
+			if end < 0 {
+				end = len(fieldPath) - i
+			}
+			segments = append(segments, fieldPathSegment{Key: fieldPath[i : i+end]})
+			i += end
+		}
+	}
+	return segments, nil
+}
@@ -0,0 +1,238 @@
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+)
+
+const manifestFieldsContent = `# Privileged workload
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  labels:
+    app.kubernetes.io/name: web
+spec:
+  containers:
+    - name: web
+      image: nginx
+      securityContext:
+        privileged: true
+`
+
+func TestFindManifestField(t *testing.T) {
+	documents, err := decodeManifestDocuments([]byte(manifestFieldsContent))
+	if err != nil {
+		t.Fatalf("decodeManifestDocuments() failed: %v", err)
+	}
+	node := documents[0].Node
+
+	tests := []struct {
+		fieldPath string
+		line      int
+		column    int
+		value     string
+	}{
+		{"metadata.name", 5, 3, "web"},
+		{`metadata.labels["app.kubernetes.io/name"]`, 7, 5, "web"},
+		{"spec.containers[0]", 10, 7, ""},
+		{"spec.containers[0].securityContext.privileged", 13, 9, "true"},
+		{"spec.containers[1].name", 0, 0, ""},
+		{"spec.volumes", 0, 0, ""},
+	}
+
+	for _, tt := range tests {
+		field, ok := findManifestField(node, tt.fieldPath)
+		if !ok {
+			if tt.line != 0 {
+				t.Errorf("findManifestField(%s) not found", tt.fieldPath)
+			}
+			continue
+		}
+		if field.Line != tt.line || field.Column != tt.column || field.Value.Value != tt.value {
+			t.Errorf("findManifestField(%s) = %d:%d %q, expected %d:%d %q", tt.fieldPath, field.Line, field.Column, field.Value.Value, tt.line, tt.column, tt.value)
+		}
+		if field.FieldPath != tt.fieldPath {
+			t.Errorf("findManifestField(%s) field path = %s", tt.fieldPath, field.FieldPath)
+		}
+	}
+}
+
+func TestGetManifestFields(t *testing.T) {
+	documents, err := decodeManifestDocuments([]byte(manifestFieldsContent))
+	if err != nil {
+		t.Fatalf("decodeManifestDocuments() failed: %v", err)
+	}
+
+	var got []string
+	for _, field := range getManifestFields(documents[0].Node, nil, "") {
+		got = append(got, field.FieldPath)
+
+		// Every field can be found back with its path
+		if found, ok := findManifestField(documents[0].Node, field.FieldPath); !ok || found.Line != field.Line || !reflect.DeepEqual(found.Keys, field.Keys) {
+			t.Errorf("findManifestField(%s) does not match the field", field.FieldPath)
+		}
+	}
+
+	expected := []string{
+		"apiVersion",
+		"kind",
+		"metadata",
+		"metadata.name",
+		"metadata.labels",
+		`metadata.labels["app.kubernetes.io/name"]`,
+		"spec",
+		"spec.containers",
+		"spec.containers[0]",
+		"spec.containers[0].name",
+		"spec.containers[0].image",
+		"spec.containers[0].securityContext",
+		"spec.containers[0].securityContext.privileged",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("getManifestFields() = %v, expected %v", got, expected)
+	}
+}
+
+func TestParseFieldPath(t *testing.T) {
+	segments, err := parseFieldPath(`spec.template["a.b[0]"][2].c`)
+	if err != nil {
+		t.Fatalf("parseFieldPath() failed: %v", err)
+	}
+	expected := []fieldPathSegment{{Key: "spec"}, {Key: "template"}, {Key: "a.b[0]"}, {Index: 2, IsIndex: true}, {Key: "c"}}
+	if !reflect.DeepEqual(segments, expected) {
+		t.Errorf("parseFieldPath() = %+v, expected %+v", segments, expected)
+	}
+
+	for _, invalid := range []string{"spec[", "spec[x]", `spec["a"`} {
+		if _, err := parseFieldPath(invalid); err == nil {
+			t.Errorf("parseFieldPath(%s) expected an error", invalid)
+		}
+	}
+}
+
+func TestDecodeManifestDocuments(t *testing.T) {
+	type item struct {
+		kind      string
+		startLine int
+		endLine   int
+	}
+
+	tests := []struct {
+		name     string
+		content  string
+		expected []item
+	}{
+		{
+			name:     "single object",
+			content:  "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n",
+			expected: []item{{"Pod", 1, 4}},
+		},
+		{
+			name:     "leading comments, crlf and literal block",
+			content:  "# config\r\n---\r\napiVersion: v1\r\nkind: ConfigMap\r\ndata:\r\n  script: |\r\n    echo one\r\n    echo two\r\n\r\n---\r\n\r\napiVersion: v1\r\nkind: Secret\r\n",
+			expected: []item{{"ConfigMap", 3, 8}, {"Secret", 12, 13}},
+		},
+		{
+			name: "kubectl get -o yaml",
+			content: `apiVersion: v1
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: web
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: web
+kind: List
+metadata:
+  resourceVersion: ""
+`,
+			expected: []item{{"Service", 3, 6}, {"Deployment", 7, 10}},
+		},
+		{
+			name: "typed list as last key",
+			content: `apiVersion: v1
+kind: ConfigMapList
+items:
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: settings
+`,
+			expected: []item{{"ConfigMap", 4, 7}},
+		},
+		{
+			name: "json array",
+			content: `[
+  {"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "prod"}},
+  {
+    "apiVersion": "v1",
+    "kind": "ServiceAccount",
+    "metadata": {
+      "name": "web",
+      "namespace": "prod"
+    }
+  }
+]`,
+			expected: []item{{"Namespace", 2, 2}, {"ServiceAccount", 3, 10}},
+		},
+		{
+			name:     "document with no kind",
+			content:  "# defaults\nreplicas: 3\n---\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: prod\n",
+			expected: []item{{"Namespace", 4, 7}},
+		},
+		{
+			name: "list items with no kind",
+			content: `apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  metadata:
+    name: orphan
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: web
+`,
+			expected: []item{{"Service", 7, 10}},
+		},
+		{
+			name: "typed list items with no kind",
+			content: `apiVersion: v1
+kind: PodList
+items:
+- metadata:
+    name: web
+`,
+			expected: []item{{"Pod", 4, 5}},
+		},
+		{
+			name:     "json array items with no kind",
+			content:  `[{"apiVersion": "v1", "metadata": {"name": "orphan"}}, {"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "prod"}}]`,
+			expected: []item{{"Namespace", 1, 1}},
+		},
+		{
+			name:     "object with items",
+			content:  "apiVersion: example.com/v1\nkind: Basket\nitems:\n- name: apple\n",
+			expected: []item{{"Basket", 1, 4}},
+		},
+	}
+
+	for _, tt := range tests {
+		documents, err := decodeManifestDocuments([]byte(tt.content))
+		if err != nil {
+			t.Errorf("%s: decodeManifestDocuments() failed: %v", tt.name, err)
+			continue
+		}
+
+		var got []item
+		for _, document := range documents {
+			got = append(got, item{document.Object.GetKind(), document.StartLine, document.EndLine})
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: decodeManifestDocuments() = %v, expected %v", tt.name, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package kubernetes
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+// Utils functions to scope the connections to the namespaces set with the namespaces and exclude_namespaces arguments
+
+// isNamespaceIncluded checks the namespace of a resource against the namespaces and exclude_namespaces arguments of the connection config.
+// The cluster-scoped resources, i.e. without a namespace, are always included.
+func isNamespaceIncluded(d *plugin.QueryData, namespace string) bool {
+	if namespace == "" {
+		return true
+	}
+	return namespaceMatchesConfig(GetConfig(d.Connection), namespace)
+}
+
+// namespaceMatchesConfig checks if the namespace is in the allow list, if any, and not excluded
+func namespaceMatchesConfig(kubernetesConfig kubernetesConfig, namespace string) bool {
+	if len(kubernetesConfig.Namespaces) > 0 && !matchesNamespacePatterns(kubernetesConfig.Namespaces, namespace) {
+		return false
+	}
+	return !matchesNamespacePatterns(kubernetesConfig.ExcludeNamespaces, namespace)
+}
+
+// matchesNamespacePatterns checks if the namespace matches any of the given names or wildcard patterns, e.g. `team-a-*`
+func matchesNamespacePatterns(patterns []string, namespace string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, namespace); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parsedContentNamespace returns the namespace of a resource read from the manifest files, the Helm charts or the snapshots,
+// or its name for the namespaces themselves. The resources without a namespace in the manifest files are always included.
+func parsedContentNamespace(content parsedContent) string {
+	obj, err := meta.Accessor(content.ParsedData)
+	if err != nil {
+		return ""
+	}
+	if content.Kind == "Namespace" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace()
+}
+
+// getListNamespace returns the namespace to list the namespaced resources from. The resources are listed from the namespace
+// set in the quals or, if a single namespace is included in the connection, from that namespace. Otherwise, the resources
+// are listed from all the namespaces, i.e. "", and the resources of the namespaces which are not included are filtered out.
+func getListNamespace(ctx context.Context, d *plugin.QueryData) string {
+	if namespace := d.EqualsQualString("namespace"); namespace != "" {
+		return namespace
+	}
+
+	if len(GetConfig(d.Connection).Namespaces) == 0 {
+		return ""
+	}
+
+	namespaces := getIncludedNamespaces(ctx, d)
+	if len(namespaces) == 1 {
+		return namespaces[0]
+	}
+	return ""
+}
+
+// includedNamespacesTTL is how long the wildcard patterns of the allow list are resolved for, so that the namespaces
+// created or deleted meanwhile are eventually picked up
+const includedNamespacesTTL = 5 * time.Minute
+
+// getIncludedNamespaces returns the namespaces included in the connection, if they can be resolved.
+// The names of the allow list are used as is. The wildcard patterns are resolved against the namespaces of the cluster,
+// which cannot be listed with the permissions of all the users though. The resolved namespaces and the failures to list
+// them are cached for includedNamespacesTTL.
+func getIncludedNamespaces(ctx context.Context, d *plugin.QueryData) []string {
+	cacheKey := "getIncludedNamespaces"
+	if cachedData, ok := d.ConnectionManager.Cache.Get(cacheKey); ok {
+		return cachedData.([]string)
+	}
+
+	kubernetesConfig := GetConfig(d.Connection)
+
+	var namespaces []string
+	if !hasNamespacePatterns(kubernetesConfig.Namespaces) {
+		for _, namespace := range kubernetesConfig.Namespaces {
+			if namespaceMatchesConfig(kubernetesConfig, namespace) {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+	} else {
+		clientset, err := GetNewClientset(ctx, d)
+		if err != nil || clientset == nil {
+			return nil
+		}
+
+		// Only the names of the namespaces are needed, so the list is not paginated
+		response, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			plugin.Logger(ctx).Warn("getIncludedNamespaces", "failed to list the namespaces", err)
+			d.ConnectionManager.Cache.SetWithTTL(cacheKey, []string(nil), includedNamespacesTTL)
+			return nil
+		}
+		for _, namespace := range response.Items {
+			if namespaceMatchesConfig(kubernetesConfig, namespace.Name) {
+				namespaces = append(namespaces, namespace.Name)
+			}
+		}
+	}
+
+	d.ConnectionManager.Cache.SetWithTTL(cacheKey, namespaces, includedNamespacesTTL)
+
+	return namespaces
+}
+
+// hasNamespacePatterns checks if any of the namespaces is a wildcard pattern
+func hasNamespacePatterns(namespaces []string) bool {
+	for _, namespace := range namespaces {
+		if strings.ContainsAny(namespace, `*?[\`) {
+			return true
+		}
+	}
+	return false
+}
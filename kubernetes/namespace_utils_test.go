@@ -0,0 +1,53 @@
+package kubernetes
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNamespaceMatchesConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    kubernetesConfig
+		namespace string
+		expected  bool
+	}{
+		{name: "no scoping", config: kubernetesConfig{}, namespace: "kube-system", expected: true},
+		{name: "allowed name", config: kubernetesConfig{Namespaces: []string{"team-a"}}, namespace: "team-a", expected: true},
+		{name: "other name", config: kubernetesConfig{Namespaces: []string{"team-a"}}, namespace: "team-b", expected: false},
+		{name: "allowed pattern", config: kubernetesConfig{Namespaces: []string{"team-a-*"}}, namespace: "team-a-prod", expected: true},
+		{name: "excluded name", config: kubernetesConfig{ExcludeNamespaces: []string{"kube-system"}}, namespace: "kube-system", expected: false},
+		{name: "excluded pattern", config: kubernetesConfig{ExcludeNamespaces: []string{"kube-*"}}, namespace: "kube-public", expected: false},
+		{name: "allowed but excluded", config: kubernetesConfig{Namespaces: []string{"team-a-*"}, ExcludeNamespaces: []string{"team-a-sandbox"}}, namespace: "team-a-sandbox", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := namespaceMatchesConfig(tt.config, tt.namespace); actual != tt.expected {
+				t.Errorf("namespaceMatchesConfig(%q) = %v, want %v", tt.namespace, actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHasNamespacePatterns(t *testing.T) {
+	if hasNamespacePatterns([]string{"team-a", "team-b"}) {
+		t.Errorf("hasNamespacePatterns(names) = true, want false")
+	}
+	if !hasNamespacePatterns([]string{"team-a", "team-b-*"}) {
+		t.Errorf("hasNamespacePatterns(pattern) = false, want true")
+	}
+}
+
+func TestParsedContentNamespace(t *testing.T) {
+	pod := parsedContent{Kind: "Pod", ParsedData: &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-a"}}}
+	if namespace := parsedContentNamespace(pod); namespace != "team-a" {
+		t.Errorf("parsedContentNamespace(pod) = %q, want team-a", namespace)
+	}
+
+	namespace := parsedContent{Kind: "Namespace", ParsedData: &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}}}
+	if name := parsedContentNamespace(namespace); name != "team-b" {
+		t.Errorf("parsedContentNamespace(namespace) = %q, want team-b", name)
+	}
+}
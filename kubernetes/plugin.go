@@ -9,6 +9,7 @@ package kubernetes
 import (
 	"context"
 	"path"
+	"reflect"
 	"regexp"
 	"slices"
 	"sort"
@@ -47,47 +48,11 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 }
 
 func pluginTableDefinitions(ctx context.Context, d *plugin.TableMapData) (map[string]*plugin.Table, error) {
+	// Watch the configured Helm charts, values files and snapshot directories for changes
+	setDerivedWatchPaths(d.Connection)
+
 	// Initialize tables
-	tables := map[string]*plugin.Table{
-		"helm_chart":                            tableHelmChart(ctx),
-		"helm_release":                          tableHelmRelease(ctx),
-		"helm_template":                         tableHelmTemplates(ctx),
-		"helm_template_rendered":                tableHelmTemplateRendered(ctx),
-		"helm_value":                            tableHelmValue(ctx),
-		"kubernetes_cluster_role":               tableKubernetesClusterRole(ctx),
-		"kubernetes_cluster_role_binding":       tableKubernetesClusterRoleBinding(ctx),
-		"kubernetes_config_map":                 tableKubernetesConfigMap(ctx),
-		"kubernetes_cronjob":                    tableKubernetesCronJob(ctx),
-		"kubernetes_custom_resource_definition": tableKubernetesCustomResourceDefinition(ctx),
-		"kubernetes_daemonset":                  tableKubernetesDaemonset(ctx),
-		"kubernetes_deployment":                 tableKubernetesDeployment(ctx),
-		"kubernetes_endpoint":                   tableKubernetesEndpoints(ctx),
-		"kubernetes_endpoint_slice":             tableKubernetesEndpointSlice(ctx),
-		"kubernetes_event":                      tableKubernetesEvent(ctx),
-		"kubernetes_horizontal_pod_autoscaler":  tableKubernetesHorizontalPodAutoscaler(ctx),
-		"kubernetes_ingress":                    tableKubernetesIngress(ctx),
-		"kubernetes_job":                        tableKubernetesJob(ctx),
-		"kubernetes_limit_range":                tableKubernetesLimitRange(ctx),
-		"kubernetes_namespace":                  tableKubernetesNamespace(ctx),
-		"kubernetes_network_policy":             tableKubernetesNetworkPolicy(ctx),
-		"kubernetes_node":                       tableKubernetesNode(ctx),
-		"kubernetes_persistent_volume":          tableKubernetesPersistentVolume(ctx),
-		"kubernetes_persistent_volume_claim":    tableKubernetesPersistentVolumeClaim(ctx),
-		"kubernetes_pod":                        tableKubernetesPod(ctx),
-		"kubernetes_pod_disruption_budget":      tableKubernetesPDB(ctx),
-		"kubernetes_pod_security_policy":        tableKubernetesPodSecurityPolicy(ctx),
-		"kubernetes_pod_template":               tableKubernetesPodTemplate(ctx),
-		"kubernetes_replicaset":                 tableKubernetesReplicaSet(ctx),
-		"kubernetes_replication_controller":     tableKubernetesReplicaController(ctx),
-		"kubernetes_resource_quota":             tableKubernetesResourceQuota(ctx),
-		"kubernetes_role":                       tableKubernetesRole(ctx),
-		"kubernetes_role_binding":               tableKubernetesRoleBinding(ctx),
-		"kubernetes_secret":                     tableKubernetesSecret(ctx),
-		"kubernetes_service":                    tableKubernetesService(ctx),
-		"kubernetes_service_account":            tableKubernetesServiceAccount(ctx),
-		"kubernetes_stateful_set":               tableKubernetesStatefulSet(ctx),
-		"kubernetes_storage_class":              tableKubernetesStorageClass(ctx),
-	}
+	tables := getStaticTables(ctx)
 
 	// Fetch available CRDs
 	crds, err := listK8sDynamicCRDs(ctx, d.ConnectionCache, d.Connection)
@@ -96,40 +61,218 @@ func pluginTableDefinitions(ctx context.Context, d *plugin.TableMapData) (map[st
 		return tables, nil
 	}
 
+	// The depth of the nested spec and status properties to generate the columns for.
+	// Defaults to 1, i.e. a column per top level property.
+	columnDepth := 1
+	if config := GetConfig(d.Connection); config.CustomResourceDepth != nil {
+		columnDepth = *config.CustomResourceDepth
+	}
+	ctx = context.WithValue(ctx, contextKey("ColumnDepth"), columnDepth)
+
+	// Generate a table per served version of the CRDs, for the versions whose schema differs from the default version
+	versionTables := false
+	if config := GetConfig(d.Connection); config.CustomResourceVersionTables != nil {
+		versionTables = *config.CustomResourceVersionTables
+	}
+
+	// Generate the table aliases from the short names and categories of the CRDs
+	tableAliases := false
+	if config := GetConfig(d.Connection); config.CustomResourceTableAliases != nil {
+		tableAliases = *config.CustomResourceTableAliases
+	}
+	aliasCtxs := map[string][]context.Context{}
+
 	for _, crd := range crds {
 		ctx = context.WithValue(ctx, contextKey("CRDName"), crd.Name)
 		ctx = context.WithValue(ctx, contextKey("CustomResourceName"), crd.Spec.Names.Plural)
-		ctx = context.WithValue(ctx, contextKey("CustomResourceNameSingular"), crd.Spec.Names.Singular)
+		ctx = context.WithValue(ctx, contextKey("CustomResourceKind"), crd.Spec.Names.Kind)
 		ctx = context.WithValue(ctx, contextKey("GroupName"), crd.Spec.Group)
+		ctx = context.WithValue(ctx, contextKey("Scope"), string(crd.Spec.Scope))
+
+		defaultVersion := getCustomResourceDefaultVersion(crd)
+		if defaultVersion == nil {
+			plugin.Logger(ctx).Warn("pluginTableDefinitions", "no served version found for the CRD", crd.Name)
+			continue
+		}
+
+		// The table of the default version accepts the manifest resources of any served version
+		var servedVersions []string
 		for _, version := range crd.Spec.Versions {
 			if version.Served {
-				ctx = context.WithValue(ctx, contextKey("ActiveVersion"), version.Name)
-				if version.Schema != nil && version.Schema.OpenAPIV3Schema != nil {
-					ctx = context.WithValue(ctx, contextKey("VersionSchemaSpec"), version.Schema.OpenAPIV3Schema.Properties["spec"])
-					ctx = context.WithValue(ctx, contextKey("VersionSchemaStatus"), version.Schema.OpenAPIV3Schema.Properties["status"])
-					if len(version.Schema.OpenAPIV3Schema.Description) > 0 {
-						ctx = context.WithValue(ctx, contextKey("VersionSchemaDescription"), strings.TrimSuffix(version.Schema.OpenAPIV3Schema.Description, ".")+".")
-					}
-				}
+				servedVersions = append(servedVersions, version.Name)
 			}
 		}
+		versionCtx := setCustomResourceVersionContext(ctx, *defaultVersion, servedVersions)
 
-		// add the tables in snake case
-		// if there is any name collision, plugin will create the dynamic tables in below order:
-		// plugin will use singular name for the first one, e.g. kubernetes_certificate
-		// plugin will use fully qualified names for the subsequent ones, e.g. kubernetes_certificate_cert_manager_io
+		tableNames := getCustomResourceTableNames(crd, tables)
+		for _, tableName := range tableNames {
+			tables[tableName] = tableKubernetesCustomResource(context.WithValue(versionCtx, contextKey("TableName"), tableName))
+		}
+
+		if tableAliases {
+			for _, alias := range getCustomResourceTableAliases(crd) {
+				aliasCtxs[alias] = append(aliasCtxs[alias], versionCtx)
+			}
+		}
+
+		if !versionTables {
+			continue
+		}
+
+		// add the version specific tables, e.g. kubernetes_certificate_v1alpha2
 		re := regexp.MustCompile(`[-.]`)
-		if tables["kubernetes_"+crd.Spec.Names.Singular] == nil {
-			ctx = context.WithValue(ctx, contextKey("TableName"), "kubernetes_"+crd.Spec.Names.Singular)
-			tables["kubernetes_"+crd.Spec.Names.Singular] = tableKubernetesCustomResource(ctx)
+		for _, version := range crd.Spec.Versions {
+			if !version.Served || version.Name == defaultVersion.Name || reflect.DeepEqual(version.Schema, defaultVersion.Schema) {
+				continue
+			}
+
+			versionCtx := setCustomResourceVersionContext(ctx, version, []string{version.Name})
+			for _, tableName := range tableNames {
+				versionTableName := tableName + "_" + re.ReplaceAllString(version.Name, "_")
+				if tables[versionTableName] == nil {
+					tables[versionTableName] = tableKubernetesCustomResource(context.WithValue(versionCtx, contextKey("TableName"), versionTableName))
+				}
+			}
+		}
+	}
+
+	// The aliases are added once all the tables are created, and only if they are not shared by multiple CRDs,
+	// e.g. the `all` category, or already used as a table name
+	for alias, aliasCtx := range aliasCtxs {
+		if len(aliasCtx) == 1 && tables[alias] == nil {
+			tables[alias] = tableKubernetesCustomResource(context.WithValue(aliasCtx[0], contextKey("TableName"), alias))
 		}
-		ctx = context.WithValue(ctx, contextKey("TableName"), "kubernetes_"+crd.Spec.Names.Singular+"_"+re.ReplaceAllString(crd.Spec.Group, "_"))
-		tables["kubernetes_"+crd.Spec.Names.Singular+"_"+re.ReplaceAllString(crd.Spec.Group, "_")] = tableKubernetesCustomResource(ctx)
 	}
 
 	return tables, nil
 }
 
+// getStaticTables returns the tables which do not depend on the CRDs of the cluster
+func getStaticTables(ctx context.Context) map[string]*plugin.Table {
+	return map[string]*plugin.Table{
+		"helm_chart":                                     tableHelmChart(ctx),
+		"helm_chart_dependency":                          tableHelmChartDependency(ctx),
+		"helm_release":                                   tableHelmRelease(ctx),
+		"helm_release_history":                           tableHelmReleaseHistory(ctx),
+		"helm_release_resource":                          tableHelmReleaseResource(ctx),
+		"helm_release_revision_diff":                     tableHelmReleaseRevisionDiff(ctx),
+		"helm_template":                                  tableHelmTemplates(ctx),
+		"helm_template_rendered":                         tableHelmTemplateRendered(ctx),
+		"helm_value":                                     tableHelmValue(ctx),
+		"helm_value_schema":                              tableHelmValueSchema(ctx),
+		"helm_value_schema_violation":                    tableHelmValueSchemaViolation(ctx),
+		"kubernetes_api_resource":                        tableKubernetesAPIResource(ctx),
+		"kubernetes_cluster":                             tableKubernetesCluster(ctx),
+		"kubernetes_cluster_role":                        tableKubernetesClusterRole(ctx),
+		"kubernetes_cluster_role_binding":                tableKubernetesClusterRoleBinding(ctx),
+		"kubernetes_config_map":                          tableKubernetesConfigMap(ctx),
+		"kubernetes_connection_info":                     tableKubernetesConnectionInfo(ctx),
+		"kubernetes_cronjob":                             tableKubernetesCronJob(ctx),
+		"kubernetes_custom_resource_definition":          tableKubernetesCustomResourceDefinition(ctx),
+		"kubernetes_custom_resource_definition_property": tableKubernetesCustomResourceDefinitionProperty(ctx),
+		"kubernetes_daemonset":                           tableKubernetesDaemonset(ctx),
+		"kubernetes_deployment":                          tableKubernetesDeployment(ctx),
+		"kubernetes_endpoint":                            tableKubernetesEndpoints(ctx),
+		"kubernetes_endpoint_slice":                      tableKubernetesEndpointSlice(ctx),
+		"kubernetes_event":                               tableKubernetesEvent(ctx),
+		"kubernetes_horizontal_pod_autoscaler":           tableKubernetesHorizontalPodAutoscaler(ctx),
+		"kubernetes_ingress":                             tableKubernetesIngress(ctx),
+		"kubernetes_job":                                 tableKubernetesJob(ctx),
+		"kubernetes_kubeconfig_cluster":                  tableKubernetesKubeconfigCluster(ctx),
+		"kubernetes_kubeconfig_context":                  tableKubernetesKubeconfigContext(ctx),
+		"kubernetes_kubeconfig_user":                     tableKubernetesKubeconfigUser(ctx),
+		"kubernetes_limit_range":                         tableKubernetesLimitRange(ctx),
+		"kubernetes_manifest_field":                      tableKubernetesManifestField(ctx),
+		"kubernetes_namespace":                           tableKubernetesNamespace(ctx),
+		"kubernetes_network_policy":                      tableKubernetesNetworkPolicy(ctx),
+		"kubernetes_node":                                tableKubernetesNode(ctx),
+		"kubernetes_persistent_volume":                   tableKubernetesPersistentVolume(ctx),
+		"kubernetes_persistent_volume_claim":             tableKubernetesPersistentVolumeClaim(ctx),
+		"kubernetes_pod":                                 tableKubernetesPod(ctx),
+		"kubernetes_pod_disruption_budget":               tableKubernetesPDB(ctx),
+		"kubernetes_pod_security_policy":                 tableKubernetesPodSecurityPolicy(ctx),
+		"kubernetes_pod_template":                        tableKubernetesPodTemplate(ctx),
+		"kubernetes_replicaset":                          tableKubernetesReplicaSet(ctx),
+		"kubernetes_replication_controller":              tableKubernetesReplicaController(ctx),
+		"kubernetes_resource":                            tableKubernetesResource(ctx),
+		"kubernetes_resource_quota":                      tableKubernetesResourceQuota(ctx),
+		"kubernetes_role":                                tableKubernetesRole(ctx),
+		"kubernetes_role_binding":                        tableKubernetesRoleBinding(ctx),
+		"kubernetes_secret":                              tableKubernetesSecret(ctx),
+		"kubernetes_service":                             tableKubernetesService(ctx),
+		"kubernetes_service_account":                     tableKubernetesServiceAccount(ctx),
+		"kubernetes_stateful_set":                        tableKubernetesStatefulSet(ctx),
+		"kubernetes_storage_class":                       tableKubernetesStorageClass(ctx),
+		"kubernetes_upgrade_readiness":                   tableKubernetesUpgradeReadiness(ctx),
+	}
+}
+
+// getCustomResourceTableNames returns the names of the tables of the given CRD in snake case, given the tables already defined.
+// If there is any name collision, plugin will create the dynamic tables in below order:
+// plugin will use singular name for the first one, e.g. kubernetes_certificate
+// plugin will use fully qualified names for the subsequent ones, e.g. kubernetes_certificate_cert_manager_io
+func getCustomResourceTableNames(crd v1.CustomResourceDefinition, tables map[string]*plugin.Table) []string {
+	re := regexp.MustCompile(`[-.]`)
+	tableNames := []string{"kubernetes_" + crd.Spec.Names.Singular + "_" + re.ReplaceAllString(crd.Spec.Group, "_")}
+	if tables["kubernetes_"+crd.Spec.Names.Singular] == nil {
+		tableNames = append([]string{"kubernetes_" + crd.Spec.Names.Singular}, tableNames...)
+	}
+	return tableNames
+}
+
+// getCustomResourceTableAliases returns the table names generated from the short names and categories of the given CRD,
+// e.g. kubernetes_cert for the `cert` short name of certificates.cert-manager.io
+func getCustomResourceTableAliases(crd v1.CustomResourceDefinition) []string {
+	re := regexp.MustCompile(`[-.]`)
+
+	var aliases []string
+	for _, name := range append(slices.Clone(crd.Spec.Names.ShortNames), crd.Spec.Names.Categories...) {
+		alias := "kubernetes_" + re.ReplaceAllString(strings.ToLower(name), "_")
+		if !slices.Contains(aliases, alias) {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+// getCustomResourceDefaultVersion returns the version used by the table of the given CRD, which is the storage version.
+// If the storage version is not served, the last served version is used instead.
+func getCustomResourceDefaultVersion(crd v1.CustomResourceDefinition) *v1.CustomResourceDefinitionVersion {
+	var defaultVersion *v1.CustomResourceDefinitionVersion
+	for i, version := range crd.Spec.Versions {
+		if !version.Served {
+			continue
+		}
+		if version.Storage {
+			return &crd.Spec.Versions[i]
+		}
+		defaultVersion = &crd.Spec.Versions[i]
+	}
+	return defaultVersion
+}
+
+// setCustomResourceVersionContext sets the context values describing the given version of the CRD, used to build its table
+func setCustomResourceVersionContext(ctx context.Context, version v1.CustomResourceDefinitionVersion, servedVersions []string) context.Context {
+	var schemaSpec, schemaStatus v1.JSONSchemaProps
+	var description interface{}
+	if version.Schema != nil && version.Schema.OpenAPIV3Schema != nil {
+		schemaSpec = version.Schema.OpenAPIV3Schema.Properties["spec"]
+		schemaStatus = version.Schema.OpenAPIV3Schema.Properties["status"]
+		if len(version.Schema.OpenAPIV3Schema.Description) > 0 {
+			description = strings.TrimSuffix(version.Schema.OpenAPIV3Schema.Description, ".") + "."
+		}
+	}
+
+	ctx = context.WithValue(ctx, contextKey("ActiveVersion"), version.Name)
+	ctx = context.WithValue(ctx, contextKey("ServedVersions"), servedVersions)
+	ctx = context.WithValue(ctx, contextKey("AdditionalPrinterColumns"), version.AdditionalPrinterColumns)
+	ctx = context.WithValue(ctx, contextKey("VersionSchemaSpec"), schemaSpec)
+	ctx = context.WithValue(ctx, contextKey("VersionSchemaStatus"), schemaStatus)
+	ctx = context.WithValue(ctx, contextKey("VersionSchemaDescription"), description)
+
+	return ctx
+}
+
 func listK8sDynamicCRDs(ctx context.Context, cn *connection.ConnectionCache, c *plugin.Connection) ([]v1.CustomResourceDefinition, error) {
 	// get the crds from config if any
 	kubernetesConfig := GetConfig(c)
@@ -150,9 +293,11 @@ func listK8sDynamicCRDs(ctx context.Context, cn *connection.ConnectionCache, c *
 	temp_crd_names := []string{}
 
 	// Build the queryData
+	// The connection manager is required to cache the clients used to fetch the manifests of the Helm releases
 	queryData := &plugin.QueryData{
-		Connection:      c,
-		ConnectionCache: cn,
+		Connection:        c,
+		ConnectionCache:   cn,
+		ConnectionManager: connection.NewManager(cn),
 	}
 
 	// Parse the manifest file content based on the kind, e.g. CustomResourceDefinition
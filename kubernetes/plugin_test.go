@@ -0,0 +1,72 @@
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestGetCustomResourceDefaultVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []v1.CustomResourceDefinitionVersion
+		expected string
+	}{
+		{
+			name: "storage version",
+			versions: []v1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1", Served: true, Storage: true},
+				{Name: "v2alpha1", Served: true},
+			},
+			expected: "v1",
+		},
+		{
+			name: "storage version not served",
+			versions: []v1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: false, Storage: true},
+				{Name: "v1beta1", Served: true},
+				{Name: "v1", Served: true},
+			},
+			expected: "v1",
+		},
+		{
+			name: "no served version",
+			versions: []v1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: false, Storage: true},
+			},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		crd := v1.CustomResourceDefinition{Spec: v1.CustomResourceDefinitionSpec{Versions: tt.versions}}
+
+		var got string
+		if version := getCustomResourceDefaultVersion(crd); version != nil {
+			got = version.Name
+		}
+		if got != tt.expected {
+			t.Errorf("%s: getCustomResourceDefaultVersion() = %q, expected %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestGetCustomResourceTableAliases(t *testing.T) {
+	crd := v1.CustomResourceDefinition{
+		Spec: v1.CustomResourceDefinitionSpec{
+			Names: v1.CustomResourceDefinitionNames{
+				Kind:       "ClusterIssuer",
+				ShortNames: []string{"ci", "clusterissuers"},
+				Categories: []string{"cert-manager", "ci"},
+			},
+		},
+	}
+
+	expected := []string{"kubernetes_ci", "kubernetes_clusterissuers", "kubernetes_cert_manager"}
+	got := getCustomResourceTableAliases(crd)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("getCustomResourceTableAliases() = %v, expected %v", got, expected)
+	}
+}
@@ -0,0 +1,217 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/turbot/steampipe-plugin-sdk/v5/connection"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// Utils functions to tune the requests to the API server, i.e. the client-side rate limiting, the timeouts and the retries
+// of the requests failing with transient errors, e.g. when throttled by the API priority and fairness.
+
+const (
+	defaultMaxErrorRetryAttempts = 3
+	defaultMinErrorRetryDelay    = 100 * time.Millisecond
+	defaultMaxErrorRetryDelay    = 30 * time.Second
+)
+
+// defaultRetryableStatusCodes are the status codes of the throttled requests and of the transient server errors,
+// e.g. the etcd timeouts
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// applyRequestSettings applies the qps, burst and request_timeout settings of the connection config
+func applyRequestSettings(restconfig *rest.Config, kubernetesConfig kubernetesConfig) error {
+	if kubernetesConfig.QPS != nil {
+		restconfig.QPS = float32(*kubernetesConfig.QPS)
+	}
+	if kubernetesConfig.Burst != nil {
+		if *kubernetesConfig.Burst < 1 {
+			return fmt.Errorf("invalid burst %d: must be greater than 0", *kubernetesConfig.Burst)
+		}
+		restconfig.Burst = *kubernetesConfig.Burst
+	} else if restconfig.QPS > 0 && restconfig.Burst == 0 {
+		// The burst is required along with the qps, so allow at least a second worth of requests at once
+		restconfig.Burst = max(rest.DefaultBurst, int(math.Ceil(float64(restconfig.QPS))))
+	}
+
+	if kubernetesConfig.RequestTimeout != nil {
+		timeout, err := time.ParseDuration(*kubernetesConfig.RequestTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid request_timeout %q: %w", *kubernetesConfig.RequestTimeout, err)
+		}
+		restconfig.Timeout = timeout
+	}
+
+	return nil
+}
+
+// rateLimiterMu serializes the creation of the rate limiters, so that the clients built concurrently share the same one
+var rateLimiterMu sync.Mutex
+
+// getRateLimiter returns the client-side rate limiter shared by all the clients of the connection, so that the qps and burst
+// settings limit the requests of the connection as a whole rather than of each client. Returns nil if it is disabled.
+func getRateLimiter(ctx context.Context, cc *connection.ConnectionCache, restconfig *rest.Config) flowcontrol.RateLimiter {
+	if restconfig.QPS < 0 {
+		return nil
+	}
+
+	qps, burst := restconfig.QPS, restconfig.Burst
+	if qps == 0 {
+		qps = rest.DefaultQPS
+	}
+	if burst == 0 {
+		burst = rest.DefaultBurst
+	}
+
+	rateLimiterMu.Lock()
+	defer rateLimiterMu.Unlock()
+
+	// have we already created and cached the rate limiter for these settings?
+	cacheKey := fmt.Sprintf("getRateLimiter-%g-%d", qps, burst)
+
+	if cachedData, ok := cc.Get(ctx, cacheKey); ok {
+		return cachedData.(flowcontrol.RateLimiter)
+	}
+
+	rateLimiter := flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+
+	// save rate limiter in cache
+	if err := cc.Set(ctx, cacheKey, rateLimiter); err != nil {
+		plugin.Logger(ctx).Error("getRateLimiter", "cache-set", err)
+	}
+
+	return rateLimiter
+}
+
+type retryConfig struct {
+	MaxAttempts int
+	MinDelay    time.Duration
+	MaxDelay    time.Duration
+	StatusCodes []int
+}
+
+// getRetryConfig returns the retry policy of the requests, as configured in the connection config
+func getRetryConfig(kubernetesConfig kubernetesConfig) (retryConfig, error) {
+	config := retryConfig{
+		MaxAttempts: defaultMaxErrorRetryAttempts,
+		MinDelay:    defaultMinErrorRetryDelay,
+		MaxDelay:    defaultMaxErrorRetryDelay,
+		StatusCodes: defaultRetryableStatusCodes,
+	}
+
+	if kubernetesConfig.MaxErrorRetryAttempts != nil {
+		if *kubernetesConfig.MaxErrorRetryAttempts < 0 {
+			return retryConfig{}, errors.New("max_error_retry_attempts must be greater than or equal to 0")
+		}
+		config.MaxAttempts = *kubernetesConfig.MaxErrorRetryAttempts
+	}
+	if kubernetesConfig.MinErrorRetryDelay != nil {
+		if *kubernetesConfig.MinErrorRetryDelay < 1 {
+			return retryConfig{}, errors.New("min_error_retry_delay must be greater than 0")
+		}
+		config.MinDelay = time.Duration(*kubernetesConfig.MinErrorRetryDelay) * time.Millisecond
+	}
+	if kubernetesConfig.MaxErrorRetryDelay != nil {
+		if *kubernetesConfig.MaxErrorRetryDelay < 1 {
+			return retryConfig{}, errors.New("max_error_retry_delay must be greater than 0")
+		}
+		config.MaxDelay = time.Duration(*kubernetesConfig.MaxErrorRetryDelay) * time.Millisecond
+	}
+	if config.MaxDelay < config.MinDelay {
+		return retryConfig{}, errors.New("max_error_retry_delay must be greater than or equal to min_error_retry_delay")
+	}
+	if kubernetesConfig.RetryableStatusCodes != nil {
+		config.StatusCodes = kubernetesConfig.RetryableStatusCodes
+	}
+
+	return config, nil
+}
+
+// retryTransport retries the requests failing with one of the retryable status codes, with an exponential backoff.
+// The delay requested by the API server in the Retry-After header is honoured.
+type retryTransport struct {
+	logger    hclog.Logger
+	config    retryConfig
+	transport http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.transport.RoundTrip(req)
+		if err != nil || attempt >= t.config.MaxAttempts || !slices.Contains(t.config.StatusCodes, resp.StatusCode) {
+			return withoutRetryAfter(resp), err
+		}
+
+		// The requests with a body which cannot be sent again are not retried. The plugin only reads the resources though.
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return withoutRetryAfter(resp), nil
+		}
+
+		delay := t.config.retryDelay(attempt, resp.Header.Get("Retry-After"))
+		t.logger.Debug("retryTransport.RoundTrip", "retrying the request", req.URL.Path, "status", resp.StatusCode, "attempt", attempt+1, "delay", delay)
+
+		// Discard the failed response, so that the connection can be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		retry := req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			retry.Body = body
+		}
+		req = retry
+	}
+}
+
+// withoutRetryAfter removes the Retry-After header of the response returned to client-go, which would otherwise retry
+// the throttled requests and the server errors up to 10 more times, on top of the retries of the transport
+func withoutRetryAfter(resp *http.Response) *http.Response {
+	if resp != nil {
+		resp.Header.Del("Retry-After")
+	}
+	return resp
+}
+
+// retryDelay returns the delay before the given retry attempt, starting from 0. The delay in seconds of the Retry-After
+// header takes precedence over the exponential backoff, and both are capped to the maximum delay.
+func (c retryConfig) retryDelay(attempt int, retryAfter string) time.Duration {
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		return min(time.Duration(seconds)*time.Second, c.MaxDelay)
+	}
+
+	delay := c.MinDelay
+	for i := 0; i < attempt && delay < c.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, c.MaxDelay)
+}
@@ -0,0 +1,138 @@
+package kubernetes
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/turbot/steampipe-plugin-sdk/v5/connection"
+	"k8s.io/client-go/rest"
+)
+
+func TestRetryTransport(t *testing.T) {
+	statuses := []int{http.StatusTooManyRequests, http.StatusGatewayTimeout, http.StatusOK}
+	var calls int
+	rt := &retryTransport{
+		logger: hclog.NewNullLogger(),
+		config: retryConfig{MaxAttempts: 3, MinDelay: time.Millisecond, MaxDelay: time.Millisecond, StatusCodes: defaultRetryableStatusCodes},
+		transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			status := statuses[calls]
+			calls++
+			return &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}, nil
+		}),
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://10.0.0.1:6443/api/v1/pods", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Errorf("RoundTrip() = %v, %v after %d calls; want a successful response after 3 calls", resp, err, calls)
+	}
+
+	// The other errors and the last attempt are returned as is
+	calls = 0
+	statuses = []int{http.StatusForbidden}
+	if resp, err := rt.RoundTrip(req); err != nil || resp.StatusCode != http.StatusForbidden || calls != 1 {
+		t.Errorf("RoundTrip(forbidden) = %v, %v after %d calls; want the forbidden response", resp, err, calls)
+	}
+
+	calls = 0
+	statuses = []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable}
+	rt.config.MaxAttempts = 1
+	if resp, err := rt.RoundTrip(req); err != nil || resp.StatusCode != http.StatusServiceUnavailable || calls != 2 {
+		t.Errorf("RoundTrip(unavailable) = %v, %v after %d calls; want the unavailable response after 2 calls", resp, err, calls)
+	}
+
+	// The Retry-After header of the last attempt is removed, so that client-go does not retry the request again
+	calls = 0
+	rt.config.MaxAttempts = 0
+	rt.transport = roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"1"}}, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})
+	if resp, err := rt.RoundTrip(req); err != nil || resp.Header.Get("Retry-After") != "" || calls != 1 {
+		t.Errorf("RoundTrip(throttled) = %v, %v after %d calls; want the throttled response without Retry-After", resp, err, calls)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	config := retryConfig{MinDelay: 100 * time.Millisecond, MaxDelay: 5 * time.Second}
+	tests := []struct {
+		attempt    int
+		retryAfter string
+		expected   time.Duration
+	}{
+		{attempt: 0, expected: 100 * time.Millisecond},
+		{attempt: 1, expected: 200 * time.Millisecond},
+		{attempt: 3, expected: 800 * time.Millisecond},
+		{attempt: 10, expected: 5 * time.Second},
+		{attempt: 0, retryAfter: "2", expected: 2 * time.Second},
+		{attempt: 0, retryAfter: "60", expected: 5 * time.Second},
+		{attempt: 1, retryAfter: "Wed, 21 Oct 2015 07:28:00 GMT", expected: 200 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if delay := config.retryDelay(tt.attempt, tt.retryAfter); delay != tt.expected {
+			t.Errorf("retryDelay(%d, %q) = %v, want %v", tt.attempt, tt.retryAfter, delay, tt.expected)
+		}
+	}
+}
+
+func TestGetRateLimiter(t *testing.T) {
+	cc, err := connection.NewConnectionCache("kubernetes", 1024*1024)
+	if err != nil {
+		t.Fatalf("NewConnectionCache() error = %v", err)
+	}
+	ctx := context.Background()
+
+	// The clients of the connection share the same rate limiter
+	limiter := getRateLimiter(ctx, cc, &rest.Config{QPS: 50, Burst: 100})
+	if limiter == nil || limiter.QPS() != 50 {
+		t.Fatalf("getRateLimiter() = %v, want a rate limiter of 50 qps", limiter)
+	}
+	if other := getRateLimiter(ctx, cc, &rest.Config{QPS: 50, Burst: 100}); other != limiter {
+		t.Errorf("getRateLimiter() again = %p, want the shared rate limiter %p", other, limiter)
+	}
+
+	// The defaults of client-go apply if not set
+	if limiter := getRateLimiter(ctx, cc, &rest.Config{}); limiter == nil || limiter.QPS() != rest.DefaultQPS {
+		t.Errorf("getRateLimiter(default) = %v, want a rate limiter of %v qps", limiter, rest.DefaultQPS)
+	}
+
+	// The rate limiting can be disabled
+	if limiter := getRateLimiter(ctx, cc, &rest.Config{QPS: -1}); limiter != nil {
+		t.Errorf("getRateLimiter(disabled) = %v, want nil", limiter)
+	}
+}
+
+func TestApplyRequestSettings(t *testing.T) {
+	qps := 50.0
+	timeout := "90s"
+
+	restconfig := &rest.Config{}
+	if err := applyRequestSettings(restconfig, kubernetesConfig{QPS: &qps, RequestTimeout: &timeout}); err != nil {
+		t.Fatalf("applyRequestSettings() error = %v", err)
+	}
+	if restconfig.QPS != 50 || restconfig.Burst != 50 || restconfig.Timeout != 90*time.Second {
+		t.Errorf("applyRequestSettings() = qps %v, burst %d, timeout %v; want 50, 50, 90s", restconfig.QPS, restconfig.Burst, restconfig.Timeout)
+	}
+
+	invalid := "1 minute"
+	if err := applyRequestSettings(&rest.Config{}, kubernetesConfig{RequestTimeout: &invalid}); err == nil {
+		t.Errorf("applyRequestSettings(invalid timeout) expected an error")
+	}
+}
+
+func TestGetRetryConfig(t *testing.T) {
+	config, err := getRetryConfig(kubernetesConfig{})
+	if err != nil || config.MaxAttempts != defaultMaxErrorRetryAttempts || len(config.StatusCodes) != len(defaultRetryableStatusCodes) {
+		t.Errorf("getRetryConfig(empty) = %+v, %v; want the defaults", config, err)
+	}
+
+	attempts, minDelay, maxDelay := 5, 500, 100
+	if _, err := getRetryConfig(kubernetesConfig{MaxErrorRetryAttempts: &attempts, MinErrorRetryDelay: &minDelay, MaxErrorRetryDelay: &maxDelay}); err == nil {
+		t.Errorf("getRetryConfig(max delay lower than min delay) expected an error")
+	}
+}
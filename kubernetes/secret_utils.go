@@ -0,0 +1,191 @@
+package kubernetes
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Utils functions to control the secret data returned by the tables, as set with the secret_data_mode argument
+
+// redactedValue replaces the secrets in the table columns, e.g. the secret data or the OIDC refresh tokens of the kubeconfig files
+const redactedValue = "REDACTED"
+
+const (
+	// SecretDataModeFull returns the secret data as is, i.e. base64 encoded for data
+	SecretDataModeFull = "full"
+	// SecretDataModeRedacted replaces the values of the secret data with REDACTED
+	SecretDataModeRedacted = "redacted"
+	// SecretDataModeHash replaces the values of the secret data with their SHA-256 digest, e.g. to detect the changes or the shared values
+	SecretDataModeHash = "hash"
+	// SecretDataModeKeysOnly replaces the values of the secret data with null
+	SecretDataModeKeysOnly = "keys_only"
+)
+
+// lastAppliedConfigAnnotation is set by `kubectl apply` to the applied object, including the data of the secrets
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// getSecretDataMode returns the secret_data_mode argument of the connection config. Defaults to full.
+func getSecretDataMode(kubernetesConfig kubernetesConfig) (string, error) {
+	if kubernetesConfig.SecretDataMode == nil {
+		return SecretDataModeFull, nil
+	}
+
+	mode := *kubernetesConfig.SecretDataMode
+	if !slices.Contains([]string{SecretDataModeFull, SecretDataModeRedacted, SecretDataModeHash, SecretDataModeKeysOnly}, mode) {
+		return "", fmt.Errorf("invalid secret_data_mode %q: must be one of full, redacted, hash or keys_only", mode)
+	}
+	return mode, nil
+}
+
+// secretDataValue returns the value of a secret data key to return in the given mode, other than full
+func secretDataValue(value []byte, mode string) any {
+	switch mode {
+	case SecretDataModeHash:
+		digest := sha256.Sum256(value)
+		return hex.EncodeToString(digest[:])
+	case SecretDataModeKeysOnly:
+		return nil
+	}
+	return redactedValue
+}
+
+// secretDataValues returns the data of a secret to return in the given mode, other than full
+func secretDataValues(data map[string][]byte, mode string) map[string]any {
+	if data == nil {
+		return nil
+	}
+
+	values := map[string]any{}
+	for key, value := range data {
+		values[key] = secretDataValue(value, mode)
+	}
+	return values
+}
+
+// secretDataKeys returns the keys of both the data and string data of a secret, in alphabetical order
+func secretDataKeys(data map[string][]byte, stringData map[string]string) []string {
+	keys := slices.Collect(maps.Keys(data))
+	for key := range stringData {
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// applySecretDataModeToObject applies the given mode to the data and string data of a secret in its unstructured form,
+// e.g. as returned by the dynamic client or embedded in the last applied configuration. The data values are base64 encoded,
+// so they are decoded before being hashed, to return the same digests as the kubernetes_secret table.
+func applySecretDataModeToObject(obj map[string]any, mode string) {
+	if mode == SecretDataModeFull {
+		return
+	}
+
+	if data, ok := obj["data"].(map[string]any); ok {
+		values := map[string]any{}
+		for key, value := range data {
+			encoded, _ := value.(string)
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				decoded = []byte(encoded)
+			}
+			values[key] = secretDataValue(decoded, mode)
+		}
+		obj["data"] = values
+	}
+
+	if stringData, ok := obj["stringData"].(map[string]any); ok {
+		values := map[string]any{}
+		for key, value := range stringData {
+			str, _ := value.(string)
+			values[key] = secretDataValue([]byte(str), mode)
+		}
+		obj["stringData"] = values
+	}
+
+	// The metadata and annotations are copied, as only the top-level fields of the object are replaced, e.g. to apply the mode
+	// to a shallow copy of a cached object
+	if metadata, ok := obj["metadata"].(map[string]any); ok {
+		if annotations, ok := metadata["annotations"].(map[string]any); ok {
+			if lastApplied, ok := annotations[lastAppliedConfigAnnotation].(string); ok {
+				annotations = maps.Clone(annotations)
+				annotations[lastAppliedConfigAnnotation] = applySecretDataModeToLastApplied(lastApplied, mode)
+				metadata = maps.Clone(metadata)
+				metadata["annotations"] = annotations
+				obj["metadata"] = metadata
+			}
+		}
+	}
+}
+
+// applySecretDataModeToField applies the given mode to a single field of a secret in its unstructured form, identified by
+// its keys, e.g. [data tls.crt]. The fields which cannot hold any secret data, or are missing, are returned as is.
+func applySecretDataModeToField(keys []string, value any, mode string) any {
+	if mode == SecretDataModeFull || value == nil || len(keys) == 0 || !slices.Contains([]string{"data", "stringData", "metadata"}, keys[0]) {
+		return value
+	}
+
+	// The field is wrapped into a partial secret, to apply the mode as to the whole object
+	obj := map[string]any{}
+	parent := obj
+	for _, key := range keys[:len(keys)-1] {
+		child := map[string]any{}
+		parent[key] = child
+		parent = child
+	}
+	parent[keys[len(keys)-1]] = value
+	applySecretDataModeToObject(obj, mode)
+
+	// The fields nested under a secret data value, which is not valid, are returned as this value
+	var field any = obj
+	for _, key := range keys {
+		parent, ok := field.(map[string]any)
+		if !ok {
+			break
+		}
+		field = parent[key]
+	}
+	return field
+}
+
+// isSecretObject returns true if the given apiVersion and kind are the ones of the core secrets
+func isSecretObject(apiVersion string, kind string) bool {
+	return apiVersion == "v1" && kind == "Secret"
+}
+
+// applySecretDataModeToAnnotations returns a copy of the annotations of a secret, with the given mode applied to the
+// secret data embedded in the last applied configuration, if any
+func applySecretDataModeToAnnotations(annotations map[string]string, mode string) map[string]string {
+	lastApplied, ok := annotations[lastAppliedConfigAnnotation]
+	if !ok || mode == SecretDataModeFull {
+		return annotations
+	}
+
+	annotations = maps.Clone(annotations)
+	annotations[lastAppliedConfigAnnotation] = applySecretDataModeToLastApplied(lastApplied, mode)
+	return annotations
+}
+
+// applySecretDataModeToLastApplied applies the given mode to the secret embedded in a last applied configuration annotation.
+// The annotation is redacted as a whole if it cannot be parsed.
+func applySecretDataModeToLastApplied(lastApplied string, mode string) string {
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(lastApplied), &obj); err != nil {
+		return redactedValue
+	}
+
+	// The annotation of the object itself is not expected, but would embed the secret data as well
+	applySecretDataModeToObject(obj, mode)
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return redactedValue
+	}
+	return string(data)
+}
@@ -0,0 +1,115 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGetSecretDataMode(t *testing.T) {
+	if mode, err := getSecretDataMode(kubernetesConfig{}); err != nil || mode != SecretDataModeFull {
+		t.Errorf("getSecretDataMode(empty) = %q, %v; expected full", mode, err)
+	}
+
+	hash := "hash"
+	if mode, err := getSecretDataMode(kubernetesConfig{SecretDataMode: &hash}); err != nil || mode != SecretDataModeHash {
+		t.Errorf("getSecretDataMode(hash) = %q, %v; expected hash", mode, err)
+	}
+
+	invalid := "masked"
+	if _, err := getSecretDataMode(kubernetesConfig{SecretDataMode: &invalid}); err == nil {
+		t.Errorf("getSecretDataMode(masked) expected an error")
+	}
+}
+
+func TestSecretDataValues(t *testing.T) {
+	data := map[string][]byte{"password": []byte("hunter2")}
+	tests := []struct {
+		mode     string
+		expected map[string]any
+	}{
+		{mode: SecretDataModeRedacted, expected: map[string]any{"password": redactedValue}},
+		{mode: SecretDataModeHash, expected: map[string]any{"password": "f52fbd32b2b3b86ff88ef6c490628285f482af15ddcb29541f94bcf526a3f6c7"}},
+		{mode: SecretDataModeKeysOnly, expected: map[string]any{"password": nil}},
+	}
+	for _, tt := range tests {
+		if got := secretDataValues(data, tt.mode); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("secretDataValues(%s) = %v, expected %v", tt.mode, got, tt.expected)
+		}
+	}
+}
+
+func TestSecretDataKeys(t *testing.T) {
+	expected := []string{"password", "token", "username"}
+	got := secretDataKeys(map[string][]byte{"username": nil, "password": nil}, map[string]string{"token": "", "password": ""})
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("secretDataKeys() = %v, expected %v", got, expected)
+	}
+}
+
+func TestApplySecretDataModeToAnnotations(t *testing.T) {
+	annotations := map[string]string{
+		"owner":                     "team-a",
+		lastAppliedConfigAnnotation: `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"db"},"data":{"password":"aHVudGVyMg=="},"stringData":{"token":"hunter2"}}`,
+	}
+
+	got := applySecretDataModeToAnnotations(annotations, SecretDataModeHash)
+	if got["owner"] != "team-a" {
+		t.Errorf("applySecretDataModeToAnnotations() changed the other annotations: %v", got)
+	}
+
+	var lastApplied map[string]any
+	if err := json.Unmarshal([]byte(got[lastAppliedConfigAnnotation]), &lastApplied); err != nil {
+		t.Fatalf("applySecretDataModeToAnnotations() returned an invalid last applied configuration: %v", err)
+	}
+	digest := "f52fbd32b2b3b86ff88ef6c490628285f482af15ddcb29541f94bcf526a3f6c7"
+	if lastApplied["data"].(map[string]any)["password"] != digest || lastApplied["stringData"].(map[string]any)["token"] != digest {
+		t.Errorf("applySecretDataModeToAnnotations() = %v, expected the digests of the data", lastApplied)
+	}
+
+	// The annotations of the cached resources are not modified
+	if annotations[lastAppliedConfigAnnotation] == got[lastAppliedConfigAnnotation] {
+		t.Errorf("applySecretDataModeToAnnotations() modified the given annotations")
+	}
+
+	if got := applySecretDataModeToAnnotations(map[string]string{lastAppliedConfigAnnotation: "not json"}, SecretDataModeKeysOnly); got[lastAppliedConfigAnnotation] != redactedValue {
+		t.Errorf("applySecretDataModeToAnnotations(invalid) = %v, expected the annotation to be redacted", got)
+	}
+}
+
+func TestApplySecretDataModeToField(t *testing.T) {
+	digest := "f52fbd32b2b3b86ff88ef6c490628285f482af15ddcb29541f94bcf526a3f6c7"
+	tests := []struct {
+		keys     []string
+		value    any
+		mode     string
+		expected any
+	}{
+		{keys: []string{"data", "password"}, value: "aHVudGVyMg==", mode: SecretDataModeFull, expected: "aHVudGVyMg=="},
+		{keys: []string{"data", "password"}, value: "aHVudGVyMg==", mode: SecretDataModeHash, expected: digest},
+		{keys: []string{"data", "tls.crt"}, value: "aHVudGVyMg==", mode: SecretDataModeRedacted, expected: redactedValue},
+		{keys: []string{"data"}, value: map[string]any{"password": "aHVudGVyMg=="}, mode: SecretDataModeKeysOnly, expected: map[string]any{"password": nil}},
+		{keys: []string{"stringData", "token"}, value: "hunter2", mode: SecretDataModeHash, expected: digest},
+		{keys: []string{"stringData", "token", "nested"}, value: "hunter2", mode: SecretDataModeRedacted, expected: redactedValue},
+		{keys: []string{"metadata", "name"}, value: "db", mode: SecretDataModeRedacted, expected: "db"},
+		{keys: []string{"type"}, value: "Opaque", mode: SecretDataModeRedacted, expected: "Opaque"},
+		{
+			keys:     []string{"metadata", "annotations", lastAppliedConfigAnnotation},
+			value:    `{"data":{"password":"aHVudGVyMg=="}}`,
+			mode:     SecretDataModeRedacted,
+			expected: `{"data":{"password":"REDACTED"}}`,
+		},
+	}
+	for _, tt := range tests {
+		if got := applySecretDataModeToField(tt.keys, tt.value, tt.mode); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("applySecretDataModeToField(%v, %s) = %v, expected %v", tt.keys, tt.mode, got, tt.expected)
+		}
+	}
+
+	// The metadata of the cached objects is not modified
+	metadata := map[string]any{"annotations": map[string]any{lastAppliedConfigAnnotation: `{"data":{"password":"aHVudGVyMg=="}}`}}
+	applySecretDataModeToField([]string{"metadata"}, metadata, SecretDataModeRedacted)
+	if metadata["annotations"].(map[string]any)[lastAppliedConfigAnnotation] != `{"data":{"password":"aHVudGVyMg=="}}` {
+		t.Errorf("applySecretDataModeToField() modified the given metadata: %v", metadata)
+	}
+}
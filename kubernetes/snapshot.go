@@ -0,0 +1,187 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	filehelpers "github.com/turbot/go-kit/files"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+// Utils functions for the cluster snapshots, e.g. the output of `kubectl cluster-info dump` or a must-gather tree
+
+// snapshotFileExtensions are the extensions of the files read from the snapshot directories. Other files, e.g. the pod logs, are ignored.
+var snapshotFileExtensions = []string{".json", ".yaml", ".yml"}
+
+// getSnapshotWatchPaths returns the files of the snapshot directories configured in the given config, to be watched for changes.
+// The snapshot files and glob patterns are watched as set in the snapshot_paths argument.
+func getSnapshotWatchPaths(config kubernetesConfig) []string {
+	var watchPaths []string
+	for _, p := range config.SnapshotPaths {
+		if path, err := homedir.Expand(p); err == nil && filehelpers.DirectoryExists(path) {
+			watchPaths = append(watchPaths, filepath.Join(path, "**", "*"))
+		}
+	}
+	return watchPaths
+}
+
+// getSnapshotContent returns the resources read from the configured snapshot paths
+func getSnapshotContent(ctx context.Context, d *plugin.QueryData) ([]parsedContent, error) {
+	contents, err := snapshotContentCached(ctx, d, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if contents != nil {
+		return contents.([]parsedContent), nil
+	}
+	return nil, nil
+}
+
+// Cached form of the snapshot content.
+var snapshotContentCached = plugin.HydrateFunc(snapshotContentUncached).Memoize()
+
+// snapshotContentUncached is the actual implementation of getSnapshotContent, which should
+// be run only once per connection. Do not call this directly, use
+// getSnapshotContent instead.
+func snapshotContentUncached(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (any, error) {
+	resolvedPaths, err := resolveSnapshotFilePaths(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	plugin.Logger(ctx).Debug("snapshotContentUncached", "Parsing snapshot files", "connection", d.Connection.Name, "files", len(resolvedPaths))
+
+	var parsedContents []parsedContent
+	for _, path := range resolvedPaths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			plugin.Logger(ctx).Error("snapshotContentUncached", "failed to read file", err, "path", path)
+			return nil, err
+		}
+
+		// Snapshots usually contain files which are not Kubernetes resources, e.g. the configuration of the gathering tool,
+		// so the files which cannot be parsed are skipped instead of failing the query
+		objects, err := decodeSnapshotFile(content)
+		if err != nil {
+			plugin.Logger(ctx).Warn("snapshotContentUncached", "failed to parse file", err, "path", path)
+			continue
+		}
+
+		for _, obj := range objects {
+			for _, item := range expandListItems(obj) {
+				if item.GetKind() == "" {
+					continue
+				}
+
+				// Convert the content to concrete type based on the resource kind
+				targetObj, err := convertUnstructuredDataToType(item)
+				if err != nil {
+					plugin.Logger(ctx).Warn("snapshotContentUncached", "failed to convert content into a concrete type", err, "path", path, "kind", item.GetKind(), "name", item.GetName())
+					continue
+				}
+
+				parsedContents = append(parsedContents, parsedContent{
+					ParsedData: targetObj,
+					Kind:       item.GetKind(),
+					Path:       path,
+					SourceType: "snapshot",
+				})
+			}
+		}
+	}
+
+	return parsedContents, nil
+}
+
+// decodeSnapshotFile decodes all the objects of a snapshot file, which can be a stream of YAML documents or JSON objects.
+// Unlike the manifest files, the status of the resources is kept as is.
+func decodeSnapshotFile(content []byte) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+	for {
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		// Skip the empty documents
+		if len(object) == 0 {
+			continue
+		}
+		objects = append(objects, &unstructured.Unstructured{Object: object})
+	}
+
+	return objects, nil
+}
+
+// resolveSnapshotFilePaths returns the resource files of the configured snapshot paths.
+// The directories are walked recursively, e.g. the output directory of `kubectl cluster-info dump`.
+func resolveSnapshotFilePaths(ctx context.Context, d *plugin.QueryData) ([]string, error) {
+	// Read the config
+	kubernetesConfig := GetConfig(d.Connection)
+
+	sources := getSourceTypes(kubernetesConfig)
+
+	// Return no files if snapshot not set in source_types or if we omit setting any snapshot paths
+	if !slices.Contains(sources, Snapshot.String()) {
+		return nil, nil
+	}
+	if kubernetesConfig.SnapshotPaths == nil {
+		return nil, nil
+	}
+
+	var resolvedPaths []string
+	for _, p := range kubernetesConfig.SnapshotPaths {
+		path, err := homedir.Expand(p)
+		if err != nil {
+			return nil, err
+		}
+
+		// Walk the local snapshot directories
+		if filehelpers.DirectoryExists(path) {
+			err := filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !entry.IsDir() && slices.Contains(snapshotFileExtensions, strings.ToLower(filepath.Ext(path))) {
+					resolvedPaths = append(resolvedPaths, path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// Otherwise, resolve the files matching the glob pattern, or the remote URL
+		files, err := d.GetSourceFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if filehelpers.DirectoryExists(file) || !slices.Contains(snapshotFileExtensions, strings.ToLower(filepath.Ext(file))) {
+				continue
+			}
+			resolvedPaths = append(resolvedPaths, file)
+		}
+	}
+
+	return resolvedPaths, nil
+}
@@ -0,0 +1,100 @@
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDecodeSnapshotFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{
+			name:     "json stream",
+			content:  `{"kind": "NodeList", "apiVersion": "v1", "items": []}` + "\n" + `{"kind": "PodList", "apiVersion": "v1", "items": []}`,
+			expected: []string{"NodeList", "PodList"},
+		},
+		{
+			name:     "yaml documents",
+			content:  "kind: Namespace\napiVersion: v1\nmetadata:\n  name: default\n---\n---\nkind: Pod\napiVersion: v1\n",
+			expected: []string{"Namespace", "Pod"},
+		},
+	}
+
+	for _, tt := range tests {
+		objects, err := decodeSnapshotFile([]byte(tt.content))
+		if err != nil {
+			t.Errorf("%s: decodeSnapshotFile() failed: %v", tt.name, err)
+			continue
+		}
+
+		var got []string
+		for _, object := range objects {
+			got = append(got, object.GetKind())
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: decodeSnapshotFile() kinds = %v, expected %v", tt.name, got, tt.expected)
+		}
+	}
+
+	if _, err := decodeSnapshotFile([]byte("{not json")); err == nil {
+		t.Errorf("expected an error for an invalid file")
+	}
+}
+
+func TestExpandListItems(t *testing.T) {
+	// `kubectl cluster-info dump` writes typed lists, whose items have no kind or apiVersion
+	files := []string{`{
+  "kind": "PodList",
+  "apiVersion": "v1",
+  "items": [
+    {"metadata": {"name": "web", "namespace": "prod"}, "status": {"phase": "Running"}},
+    {"kind": "Pod", "apiVersion": "v1", "metadata": {"name": "api", "namespace": "prod"}}
+  ]
+}`, `apiVersion: v1
+kind: List
+items:
+  - apiVersion: apps/v1
+    kind: Deployment
+    metadata:
+      name: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  items: "3"
+`}
+
+	var got []string
+	var pods []*unstructured.Unstructured
+	for _, content := range files {
+		objects, err := decodeSnapshotFile([]byte(content))
+		if err != nil {
+			t.Fatalf("decodeSnapshotFile() failed: %v", err)
+		}
+
+		for _, object := range objects {
+			for _, item := range expandListItems(object) {
+				got = append(got, item.GetAPIVersion()+"/"+item.GetKind()+"/"+item.GetName())
+				if item.GetKind() == "Pod" {
+					pods = append(pods, item)
+				}
+			}
+		}
+	}
+
+	expected := []string{"v1/Pod/web", "v1/Pod/api", "apps/v1/Deployment/web", "v1/ConfigMap/settings"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expandListItems() = %v, expected %v", got, expected)
+	}
+
+	if phase, _, _ := unstructured.NestedString(pods[0].Object, "status", "phase"); phase != "Running" {
+		t.Errorf("expected the status of the list items to be preserved, got phase %q", phase)
+	}
+}
@@ -0,0 +1,223 @@
+package kubernetes
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+//// TABLE DEFINITION
+
+func tableHelmChartDependency(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "helm_chart_dependency",
+		Description: "Lists the dependencies of the configured charts and their subcharts, along with the versions locked in Chart.lock and vendored in the charts directory",
+		List: &plugin.ListConfig{
+			Hydrate: listHelmChartDependencies,
+		},
+		Columns: []*plugin.Column{
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "The name of the dependency."},
+			{Name: "alias", Type: proto.ColumnType_STRING, Description: "The alias used for the dependency in the parent chart.", Transform: transform.FromField("Alias").Transform(transform.NullIfZeroValue)},
+			{Name: "parent_chart_name", Type: proto.ColumnType_STRING, Description: "The name of the chart declaring the dependency."},
+			{Name: "parent_chart_path", Type: proto.ColumnType_STRING, Description: "The path to the directory of the chart declaring the dependency."},
+			{Name: "chart_name", Type: proto.ColumnType_STRING, Description: "The name of the configured chart."},
+			{Name: "chart_path", Type: proto.ColumnType_STRING, Description: "The path to the directory where the configured chart is located."},
+			{Name: "version_constraint", Type: proto.ColumnType_STRING, Description: "The SemVer version or range of the dependency declared in Chart.yaml.", Transform: transform.FromField("VersionConstraint").Transform(transform.NullIfZeroValue)},
+			{Name: "locked_version", Type: proto.ColumnType_STRING, Description: "The version of the dependency locked in Chart.lock.", Transform: transform.FromField("LockedVersion").Transform(transform.NullIfZeroValue)},
+			{Name: "vendored_version", Type: proto.ColumnType_STRING, Description: "The version of the subchart vendored in the charts directory.", Transform: transform.FromField("VendoredVersion").Transform(transform.NullIfZeroValue)},
+			{Name: "repository", Type: proto.ColumnType_STRING, Description: "The URL to the repository of the dependency.", Transform: transform.FromField("Repository").Transform(transform.NullIfZeroValue)},
+			{Name: "condition", Type: proto.ColumnType_STRING, Description: "The YAML path(s) to the boolean values that enable or disable the dependency.", Transform: transform.FromField("Condition").Transform(transform.NullIfZeroValue)},
+			{Name: "tags", Type: proto.ColumnType_JSON, Description: "The tags used to enable or disable the dependency as a group."},
+			{Name: "import_values", Type: proto.ColumnType_JSON, Description: "The values imported from the dependency into the parent chart."},
+			{Name: "declared", Type: proto.ColumnType_BOOL, Description: "Indicates whether the dependency is declared in Chart.yaml. Subcharts placed in the charts directory without a declaration are always enabled."},
+			{Name: "vendored", Type: proto.ColumnType_BOOL, Description: "Indicates whether the subchart is present in the charts directory."},
+			{Name: "enabled", Type: proto.ColumnType_JSON, Description: "A map of the configured chart renders (the keys of the helm_rendered_charts config argument) to whether the dependency is enabled by the condition and tags in the values of the render."},
+		},
+	}
+}
+
+type helmChartDependency struct {
+	Name              string
+	Alias             string
+	ParentChartName   string
+	ParentChartPath   string
+	ChartName         string
+	ChartPath         string
+	VersionConstraint string
+	LockedVersion     string
+	VendoredVersion   string
+	Repository        string
+	Condition         string
+	Tags              []string
+	ImportValues      []interface{}
+	Declared          bool
+	Vendored          bool
+	Enabled           map[string]bool
+}
+
+//// LIST FUNCTION
+
+func listHelmChartDependencies(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	charts, err := getUniqueHelmCharts(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("listHelmChartDependencies", "failed to list charts", err)
+		return nil, err
+	}
+	kubernetesConfig := GetConfig(d.Connection)
+
+	for _, parsedChart := range charts {
+		// Get the enabled subcharts of the chart for each render config.
+		// The subcharts are identified by their full path, e.g. my-app/charts/redis.
+		enabledSubcharts := map[string][]string{}
+		for name, c := range kubernetesConfig.HelmRenderedCharts {
+			if c.ChartPath != parsedChart.Path {
+				continue
+			}
+
+			chrt, _, err := loadHelmChartForConfig(c)
+			if err != nil {
+				plugin.Logger(ctx).Error("listHelmChartDependencies", "load_chart_error", err, "config", name)
+				return nil, err
+			}
+			enabledSubcharts[name] = listHelmSubchartFullPaths(chrt)
+		}
+
+		dependencies := getHelmChartDependencies(parsedChart, parsedChart.Chart, parsedChart.Chart.ChartFullPath(), enabledSubcharts)
+		for _, dependency := range dependencies {
+			d.StreamListItem(ctx, dependency)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// getHelmChartDependencies returns the dependencies declared by the given chart as well as the undeclared subcharts vendored in its
+// charts directory, followed by the dependencies of its vendored subcharts.
+// The effective path is the full path of the chart once the aliases are applied, and is used to look up whether the dependency
+// is enabled in the processed chart of each render config.
+func getHelmChartDependencies(root *parsedHelmChart, parent *chart.Chart, effectivePath string, enabledSubcharts map[string][]string) []helmChartDependency {
+	var dependencies []helmChartDependency
+	var processedSubcharts []*chart.Chart
+
+	newDependency := func(name string) helmChartDependency {
+		return helmChartDependency{
+			Name:            name,
+			ParentChartName: parent.Name(),
+			ParentChartPath: getHelmSubchartPath(root.Chart, root.Path, parent),
+			ChartName:       root.Chart.Name(),
+			ChartPath:       root.Path,
+		}
+	}
+
+	isEnabled := func(subchartPath string) map[string]bool {
+		if len(enabledSubcharts) == 0 {
+			return nil
+		}
+		enabled := map[string]bool{}
+		for config, paths := range enabledSubcharts {
+			enabled[config] = slices.Contains(paths, subchartPath)
+		}
+		return enabled
+	}
+
+	for _, dep := range parent.Metadata.Dependencies {
+		dependency := newDependency(dep.Name)
+		dependency.Alias = dep.Alias
+		dependency.VersionConstraint = dep.Version
+		dependency.Repository = dep.Repository
+		dependency.Condition = dep.Condition
+		dependency.Tags = dep.Tags
+		dependency.ImportValues = dep.ImportValues
+		dependency.Declared = true
+
+		if parent.Lock != nil {
+			for _, locked := range parent.Lock.Dependencies {
+				if locked.Name == dep.Name && (locked.Repository == dep.Repository || locked.Repository == "") {
+					dependency.LockedVersion = locked.Version
+					break
+				}
+			}
+		}
+
+		name := dep.Name
+		if dep.Alias != "" {
+			name = dep.Alias
+		}
+		subchartPath := effectivePath + "/charts/" + name
+		dependency.Enabled = isEnabled(subchartPath)
+
+		if subchart := findVendoredHelmSubchart(parent, dep); subchart != nil {
+			dependency.Vendored = true
+			dependency.VendoredVersion = subchart.Metadata.Version
+			dependencies = append(dependencies, dependency)
+			dependencies = append(dependencies, getHelmChartDependencies(root, subchart, subchartPath, enabledSubcharts)...)
+			processedSubcharts = append(processedSubcharts, subchart)
+		} else {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	// Subcharts in the charts directory, which are not declared in Chart.yaml, are always rendered
+	for _, subchart := range parent.Dependencies() {
+		if slices.Contains(processedSubcharts, subchart) {
+			continue
+		}
+
+		subchartPath := effectivePath + "/charts/" + subchart.Name()
+		dependency := newDependency(subchart.Name())
+		dependency.Vendored = true
+		dependency.VendoredVersion = subchart.Metadata.Version
+		dependency.Enabled = isEnabled(subchartPath)
+
+		dependencies = append(dependencies, dependency)
+		dependencies = append(dependencies, getHelmChartDependencies(root, subchart, subchartPath, enabledSubcharts)...)
+	}
+
+	return dependencies
+}
+
+// findVendoredHelmSubchart returns the subchart vendored in the charts directory of the parent for the given dependency, if any.
+// A subchart named after the alias of the dependency takes precedence. Otherwise, as Helm does, the subchart is matched by its name
+// and a version satisfying the version constraint, so that a chart aliased twice with different versions resolves to each version.
+func findVendoredHelmSubchart(parent *chart.Chart, dep *chart.Dependency) *chart.Chart {
+	if dep.Alias != "" {
+		for _, subchart := range parent.Dependencies() {
+			if subchart.Name() == dep.Alias {
+				return subchart
+			}
+		}
+	}
+
+	for _, subchart := range parent.Dependencies() {
+		if subchart.Name() != dep.Name {
+			continue
+		}
+		if dep.Version == "" || chartutil.IsCompatibleRange(dep.Version, subchart.Metadata.Version) {
+			return subchart
+		}
+	}
+	return nil
+}
+
+// listHelmSubchartFullPaths returns the full path of all the subcharts in the chart tree, e.g. my-app/charts/redis
+func listHelmSubchartFullPaths(c *chart.Chart) []string {
+	var paths []string
+	for _, subchart := range c.Dependencies() {
+		paths = append(paths, subchart.ChartFullPath())
+		paths = append(paths, listHelmSubchartFullPaths(subchart)...)
+	}
+	sort.Strings(paths)
+	return paths
+}
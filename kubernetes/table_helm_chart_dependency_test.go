@@ -0,0 +1,48 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestGetHelmChartDependencies(t *testing.T) {
+	newChart := func(name string, version string) *chart.Chart {
+		return &chart.Chart{Metadata: &chart.Metadata{Name: name, Version: version, APIVersion: chart.APIVersionV2}}
+	}
+
+	root := newChart("my-app", "1.0.0")
+	root.Metadata.Dependencies = []*chart.Dependency{
+		{Name: "redis", Version: "~17.0.0", Alias: "cache"},
+		{Name: "redis", Version: "~18.0.0", Alias: "queue"},
+		{Name: "postgresql", Version: "12.1.0"},
+		{Name: "common", Version: "2.0.0", Alias: "shared"},
+	}
+	root.SetDependencies(newChart("redis", "17.0.3"), newChart("redis", "18.0.1"), newChart("shared", "2.0.0"), newChart("extra", "0.1.0"))
+
+	tests := []struct {
+		name            string
+		alias           string
+		declared        bool
+		vendored        bool
+		vendoredVersion string
+	}{
+		{name: "redis", alias: "cache", declared: true, vendored: true, vendoredVersion: "17.0.3"},
+		{name: "redis", alias: "queue", declared: true, vendored: true, vendoredVersion: "18.0.1"},
+		{name: "postgresql", declared: true, vendored: false},
+		{name: "common", alias: "shared", declared: true, vendored: true, vendoredVersion: "2.0.0"},
+		{name: "extra", declared: false, vendored: true, vendoredVersion: "0.1.0"},
+	}
+
+	dependencies := getHelmChartDependencies(&parsedHelmChart{Chart: root, Path: "/charts/my-app"}, root, root.ChartFullPath(), nil)
+	if len(dependencies) != len(tests) {
+		t.Fatalf("getHelmChartDependencies() returned %d dependencies, expected %d: %+v", len(dependencies), len(tests), dependencies)
+	}
+	for i, tt := range tests {
+		got := dependencies[i]
+		if got.Name != tt.name || got.Alias != tt.alias || got.Declared != tt.declared || got.Vendored != tt.vendored || got.VendoredVersion != tt.vendoredVersion {
+			t.Errorf("dependency %d = %s (alias %q, declared %v, vendored %v %q), expected %s (alias %q, declared %v, vendored %v %q)",
+				i, got.Name, got.Alias, got.Declared, got.Vendored, got.VendoredVersion, tt.name, tt.alias, tt.declared, tt.vendored, tt.vendoredVersion)
+		}
+	}
+}
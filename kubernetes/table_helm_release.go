@@ -2,10 +2,8 @@ package kubernetes
 
 import (
 	"context"
-	"strings"
 	"time"
 
-	"helm.sh/helm/v3/pkg/action"
 	helmTime "helm.sh/helm/v3/pkg/time"
 
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
@@ -23,24 +21,26 @@ func tableHelmRelease(ctx context.Context) *plugin.Table {
 			Hydrate:       listHelmReleases,
 			ParentHydrate: listK8sNamespaces,
 			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "name", Require: plugin.Optional},
 				{Name: "namespace", Require: plugin.Optional},
 				{Name: "status", Require: plugin.Optional},
 			},
 		},
 		Get: &plugin.GetConfig{
-			Hydrate: getHelmRelease,
-			KeyColumns: plugin.KeyColumnSlice{
-				{Name: "name", Require: plugin.Required},
-				{Name: "namespace", Require: plugin.Optional},
-			},
+			Hydrate:    getHelmRelease,
+			KeyColumns: plugin.AllColumns([]string{"name", "namespace"}),
 		},
 		Columns: []*plugin.Column{
 			{Name: "name", Type: proto.ColumnType_STRING, Description: "The name of the release."},
 			{Name: "namespace", Type: proto.ColumnType_STRING, Description: "The kubernetes namespace of the release."},
 			{Name: "version", Type: proto.ColumnType_INT, Description: "The revision of the release."},
+			{Name: "revision", Type: proto.ColumnType_INT, Description: "The revision of the release.", Transform: transform.FromField("Version")},
 			{Name: "status", Type: proto.ColumnType_STRING, Description: "The current state of the release. Possible values: deployed, failed, pending-install, pending-rollback, pending-upgrade, superseded, uninstalled, uninstalling, unknown.", Transform: transform.FromField("Info.Status").Transform(transform.ToString)},
 			{Name: "description", Type: proto.ColumnType_STRING, Description: "A human-friendly description about the release.", Transform: transform.FromField("Info.Description")},
 			{Name: "chart_name", Type: proto.ColumnType_STRING, Description: "The name of the chart that was released.", Transform: transform.FromField("Chart.Metadata.Name")},
+			{Name: "chart_version", Type: proto.ColumnType_STRING, Description: "The version of the chart that was released.", Transform: transform.FromField("Chart.Metadata.Version")},
+			{Name: "app_version", Type: proto.ColumnType_STRING, Description: "The version of the application enclosed inside of the released chart.", Transform: transform.FromField("Chart.Metadata.AppVersion").Transform(transform.NullIfZeroValue)},
+			{Name: "storage_driver", Type: proto.ColumnType_STRING, Description: "The Helm storage driver used to store the release. Possible values: secret, configmap."},
 			{Name: "first_deployed", Type: proto.ColumnType_TIMESTAMP, Description: "The time when the release was first deployed.", Transform: transform.FromField("Info.FirstDeployed").Transform(parseDateStringToTime)},
 			{Name: "last_deployed", Type: proto.ColumnType_TIMESTAMP, Description: "The time when the release was last deployed.", Transform: transform.FromField("Info.LastDeployed").Transform(parseDateStringToTime)},
 			{Name: "deleted", Type: proto.ColumnType_TIMESTAMP, Description: "The time when this object was deleted.", Transform: transform.FromField("Info.Deleted").Transform(parseDateStringToTime)},
@@ -61,8 +61,8 @@ func listHelmReleases(ctx context.Context, d *plugin.QueryData, h *plugin.Hydrat
 		return nil, nil
 	}
 
-	// By default the client uses the default namespace defined in the cluster context.
-	// So, use the namespace list as parent and get the releases from each of the namespaces available in the current cluster context.
+	// Use the namespace list as parent and get the releases from each of the namespaces available in the current cluster context.
+	// The releases of the namespaces are listed in parallel.
 	namespace := namespaceInfo.Name
 	if d.EqualsQualString("namespace") != "" {
 		namespaceQual := d.EqualsQualString("namespace")
@@ -73,54 +73,22 @@ func listHelmReleases(ctx context.Context, d *plugin.QueryData, h *plugin.Hydrat
 		}
 	}
 
-	// Create client
-	client, err := getHelmClient(ctx, namespace)
+	// Lists all revisions of the releases stored in the namespace, irrespective of the charts configured in the connection
+	revisions, err := listHelmReleaseRevisions(ctx, d, namespace, d.EqualsQualString("name"))
 	if err != nil {
-		plugin.Logger(ctx).Error("listHelmReleases", "client_error", err)
+		plugin.Logger(ctx).Error("listHelmReleases", "storage_error", err, "namespace", namespace)
 		return nil, err
 	}
 
-	// Return nil, if client is nil
-	if client == nil {
-		return nil, nil
-	}
-
-	// List all the helm charts configured in the config
-	chart, err := getUniqueHelmCharts(ctx, d)
-	if err != nil {
-		plugin.Logger(ctx).Error("listHelmReleases", "failed to list charts", err)
-		return nil, err
-	}
-
-	// Return nil, if no chart configured in the connection
-	if chart == nil {
-		return nil, nil
-	}
-
-	for _, c := range chart {
-		releaseState := action.ListAll
-		if d.EqualsQuals["status"] != nil {
-			givenState := d.EqualsQualString("status")
-			releaseState = action.ListAll.FromName(givenState)
+	for _, release := range filterLatestHelmReleases(revisions) {
+		if d.EqualsQualString("status") != "" && release.Info.Status.String() != d.EqualsQualString("status") {
+			continue
 		}
+		d.StreamListItem(ctx, release)
 
-		// Lists all releases for a specified namespace. By default it uses current namespace context, if nothing is set
-		releases, err := client.ListReleasesByStateMask(releaseState)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, release := range releases {
-			// Ignore, if the release is not for the desired chart
-			if release.Chart.Metadata.Name != c.Chart.Metadata.Name {
-				continue
-			}
-			d.StreamListItem(ctx, release)
-
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
-			}
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
 		}
 	}
 
@@ -134,26 +102,22 @@ func getHelmRelease(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateD
 	releaseName := d.EqualsQualString("name")
 
 	// Return nil, if empty
-	if releaseName == "" {
+	if namespace == "" || releaseName == "" {
 		return nil, nil
 	}
 
-	// Create client
-	client, err := getHelmClient(ctx, namespace)
+	revisions, err := listHelmReleaseRevisions(ctx, d, namespace, releaseName)
 	if err != nil {
 		return nil, err
 	}
 
-	release, err := client.GetRelease(releaseName)
-	if err != nil {
-		// Return nil, if the requested resource is not present
-		if strings.Contains(err.Error(), "not found") {
-			return nil, nil
-		}
-		return nil, err
+	// Return nil, if the requested resource is not present
+	releases := filterLatestHelmReleases(revisions)
+	if len(releases) == 0 {
+		return nil, nil
 	}
 
-	return release, nil
+	return releases[0], nil
 }
 
 //// TRANSFORM FUNCTIONS
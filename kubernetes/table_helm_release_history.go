@@ -0,0 +1,75 @@
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableHelmReleaseHistory(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "helm_release_history",
+		Description: "List all the revisions of the releases of charts in a Kubernetes cluster",
+		List: &plugin.ListConfig{
+			Hydrate:       listHelmReleaseHistory,
+			ParentHydrate: listK8sNamespaces,
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "name", Require: plugin.Optional},
+				{Name: "namespace", Require: plugin.Optional},
+				{Name: "status", Require: plugin.Optional},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "The name of the release."},
+			{Name: "namespace", Type: proto.ColumnType_STRING, Description: "The kubernetes namespace of the release."},
+			{Name: "revision", Type: proto.ColumnType_INT, Description: "The revision of the release.", Transform: transform.FromField("Version")},
+			{Name: "status", Type: proto.ColumnType_STRING, Description: "The state of the revision. Possible values: deployed, failed, pending-install, pending-rollback, pending-upgrade, superseded, uninstalled, uninstalling, unknown.", Transform: transform.FromField("Info.Status").Transform(transform.ToString)},
+			{Name: "chart_name", Type: proto.ColumnType_STRING, Description: "The name of the chart that was released.", Transform: transform.FromField("Chart.Metadata.Name")},
+			{Name: "chart_version", Type: proto.ColumnType_STRING, Description: "The version of the chart that was released.", Transform: transform.FromField("Chart.Metadata.Version")},
+			{Name: "app_version", Type: proto.ColumnType_STRING, Description: "The version of the application enclosed inside of the released chart.", Transform: transform.FromField("Chart.Metadata.AppVersion").Transform(transform.NullIfZeroValue)},
+			{Name: "updated", Type: proto.ColumnType_TIMESTAMP, Description: "The time when the revision was deployed.", Transform: transform.FromField("Info.LastDeployed").Transform(parseDateStringToTime)},
+			{Name: "description", Type: proto.ColumnType_STRING, Description: "A human-friendly description about the revision.", Transform: transform.FromField("Info.Description")},
+			{Name: "storage_driver", Type: proto.ColumnType_STRING, Description: "The Helm storage driver used to store the release. Possible values: secret, configmap."},
+			{Name: "config", Type: proto.ColumnType_JSON, Description: "The set of extra Values added to the chart in the revision."},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listHelmReleaseHistory(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	// Get the namespace information
+	namespaceInfo := h.Item.(Namespace)
+	if namespaceInfo.SourceType != "deployed" {
+		return nil, nil
+	}
+
+	namespace := namespaceInfo.Name
+	if d.EqualsQualString("namespace") != "" && d.EqualsQualString("namespace") != namespace {
+		return nil, nil
+	}
+
+	revisions, err := listHelmReleaseRevisions(ctx, d, namespace, d.EqualsQualString("name"))
+	if err != nil {
+		plugin.Logger(ctx).Error("listHelmReleaseHistory", "storage_error", err, "namespace", namespace)
+		return nil, err
+	}
+
+	for _, revision := range revisions {
+		if d.EqualsQualString("status") != "" && revision.Info.Status.String() != d.EqualsQualString("status") {
+			continue
+		}
+		d.StreamListItem(ctx, revision)
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
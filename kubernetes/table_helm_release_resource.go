@@ -0,0 +1,355 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableHelmReleaseResource(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "helm_release_resource",
+		Description: "List the objects in the manifests of the deployed releases, along with whether their live counterpart in the cluster is missing or has drifted",
+		List: &plugin.ListConfig{
+			Hydrate: listHelmReleaseResources,
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "release_name", Require: plugin.Optional},
+				{Name: "release_namespace", Require: plugin.Optional},
+				{Name: "kind", Require: plugin.Optional},
+			},
+		},
+		HydrateConfig: []plugin.HydrateConfig{
+			{Func: getHelmReleaseResourceLiveState},
+		},
+		Columns: []*plugin.Column{
+			{Name: "release_name", Type: proto.ColumnType_STRING, Description: "The name of the release.", Transform: transform.FromField("Release.Name")},
+			{Name: "release_namespace", Type: proto.ColumnType_STRING, Description: "The kubernetes namespace of the release.", Transform: transform.FromField("Release.Namespace")},
+			{Name: "release_revision", Type: proto.ColumnType_INT, Description: "The revision of the release the manifest belongs to.", Transform: transform.FromField("Release.Version")},
+			{Name: "chart_name", Type: proto.ColumnType_STRING, Description: "The name of the chart that was released.", Transform: transform.FromField("Release.Chart.Metadata.Name")},
+			{Name: "api_version", Type: proto.ColumnType_STRING, Description: "The API version of the object.", Transform: transform.FromP(helmReleaseObjectValue, "APIVersion")},
+			{Name: "kind", Type: proto.ColumnType_STRING, Description: "The kind of the object.", Transform: transform.FromP(helmReleaseObjectValue, "Kind")},
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "The name of the object.", Transform: transform.FromP(helmReleaseObjectValue, "Name")},
+			{Name: "namespace", Type: proto.ColumnType_STRING, Description: "The namespace of the object. Null for the cluster-scoped objects.", Transform: transform.FromP(helmReleaseObjectValue, "Namespace").Transform(transform.NullIfZeroValue)},
+			{Name: "missing", Type: proto.ColumnType_BOOL, Description: "True if the object does not exist in the cluster.", Hydrate: getHelmReleaseResourceLiveState},
+			{Name: "drifted", Type: proto.ColumnType_BOOL, Description: "True if any of the fields set in the manifest has a different value in the live object.", Hydrate: getHelmReleaseResourceLiveState},
+			{Name: "drifted_fields", Type: proto.ColumnType_JSON, Description: "The fields set in the manifest whose value differs in the live object, with their expected and actual values.", Hydrate: getHelmReleaseResourceLiveState},
+			{Name: "live_uid", Type: proto.ColumnType_STRING, Description: "The UID of the live object.", Hydrate: getHelmReleaseResourceLiveState, Transform: transform.FromField("UID").Transform(transform.NullIfZeroValue)},
+			{Name: "manifest", Type: proto.ColumnType_JSON, Description: "The object as defined in the manifest of the release.", Hydrate: getHelmReleaseResourceManifest, Transform: transform.FromValue()},
+		},
+	}
+}
+
+type helmReleaseResourceLiveState struct {
+	UID           string
+	Missing       bool
+	Drifted       bool
+	DriftedFields []helmDriftedField
+}
+
+type helmDriftedField struct {
+	Path     string      `json:"path"`
+	Expected interface{} `json:"expected"`
+	Actual   interface{} `json:"actual"`
+}
+
+//// LIST FUNCTION
+
+func listHelmReleaseResources(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	objects, err := getHelmReleaseObjects(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("listHelmReleaseResources", "failed to list release objects", err)
+		return nil, err
+	}
+
+	releaseName := d.EqualsQualString("release_name")
+	releaseNamespace := d.EqualsQualString("release_namespace")
+	kind := d.EqualsQualString("kind")
+
+	for _, o := range objects {
+		if (releaseName != "" && o.Release.Name != releaseName) ||
+			(releaseNamespace != "" && o.Release.Namespace != releaseNamespace) ||
+			(kind != "" && o.Object.GetKind() != kind) {
+			continue
+		}
+
+		d.StreamListItem(ctx, o)
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getHelmReleaseResourceLiveState(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	o := h.Item.(helmReleaseObject)
+
+	mapper, err := getRESTMapper(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := GetNewClientDynamic(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	if mapper == nil || clientset == nil {
+		return nil, nil
+	}
+
+	gvk := o.Object.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		// The kind is no longer served by the cluster, e.g. the CRD has been deleted
+		plugin.Logger(ctx).Warn("getHelmReleaseResourceLiveState", "mapping_error", err, "kind", gvk.String())
+		return helmReleaseResourceLiveState{Missing: true}, nil
+	}
+
+	live, err := clientset.Resource(mapping.Resource).Namespace(o.Object.GetNamespace()).Get(ctx, o.Object.GetName(), v1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return helmReleaseResourceLiveState{Missing: true}, nil
+		}
+		plugin.Logger(ctx).Error("getHelmReleaseResourceLiveState", "api_error", err)
+		return nil, err
+	}
+
+	// The API server folds the string data of the secrets into their data, and never returns it
+	expected := o.Object.Object
+	if isSecretObject(o.Object.GetAPIVersion(), o.Object.GetKind()) {
+		expected = foldHelmSecretStringData(expected)
+	}
+
+	driftedFields := getHelmDriftedFields("", expected, live.Object)
+
+	// The drift is detected on the actual secret data, but the values are returned as set in the secret_data_mode argument
+	if isSecretObject(o.Object.GetAPIVersion(), o.Object.GetKind()) {
+		mode, err := getSecretDataMode(GetConfig(d.Connection))
+		if err != nil {
+			return nil, err
+		}
+		driftedFields = applySecretDataModeToDriftedFields(driftedFields, mode)
+	}
+
+	return helmReleaseResourceLiveState{
+		UID:           string(live.GetUID()),
+		Drifted:       len(driftedFields) > 0,
+		DriftedFields: driftedFields,
+	}, nil
+}
+
+func getHelmReleaseResourceManifest(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	o := h.Item.(helmReleaseObject)
+
+	if !isSecretObject(o.Object.GetAPIVersion(), o.Object.GetKind()) {
+		return o.Object.Object, nil
+	}
+
+	// The secret data is returned as set in the secret_data_mode argument, as in the kubernetes_secret table
+	mode, err := getSecretDataMode(GetConfig(d.Connection))
+	if err != nil {
+		return nil, err
+	}
+
+	// The objects are shared by the queries of the connection, so the mode is applied to a copy
+	manifest := maps.Clone(o.Object.Object)
+	applySecretDataModeToObject(manifest, mode)
+	return manifest, nil
+}
+
+// applySecretDataModeToDriftedFields returns the drifted fields of a secret, with the given mode applied to their expected and actual values
+func applySecretDataModeToDriftedFields(fields []helmDriftedField, mode string) []helmDriftedField {
+	if mode == SecretDataModeFull {
+		return fields
+	}
+
+	masked := make([]helmDriftedField, 0, len(fields))
+	for _, field := range fields {
+		keys := helmDriftedFieldKeys(field.Path)
+		masked = append(masked, helmDriftedField{
+			Path:     field.Path,
+			Expected: applySecretDataModeToField(keys, field.Expected, mode),
+			Actual:   applySecretDataModeToField(keys, field.Actual, mode),
+		})
+	}
+	return masked
+}
+
+// helmDriftedFieldKeys returns the keys of the given drifted field path. The keys of the secret data and of the annotations
+// can contain dots, e.g. tls.crt, so they are not split.
+func helmDriftedFieldKeys(fieldPath string) []string {
+	for _, parent := range []string{"data", "stringData", "metadata.annotations"} {
+		if key, ok := strings.CutPrefix(fieldPath, parent+"."); ok {
+			return append(strings.Split(parent, "."), key)
+		}
+	}
+	return strings.Split(fieldPath, ".")
+}
+
+// foldHelmSecretStringData returns a copy of the given secret with its string data merged into its base64 encoded data,
+// as done by the API server. The string data takes precedence over the data with the same key.
+func foldHelmSecretStringData(obj map[string]interface{}) map[string]interface{} {
+	stringData, ok := obj["stringData"].(map[string]interface{})
+	if !ok {
+		return obj
+	}
+
+	data := map[string]interface{}{}
+	if existing, ok := obj["data"].(map[string]interface{}); ok {
+		maps.Copy(data, existing)
+	}
+	for key, value := range stringData {
+		str, _ := value.(string)
+		data[key] = base64.StdEncoding.EncodeToString([]byte(str))
+	}
+
+	folded := maps.Clone(obj)
+	delete(folded, "stringData")
+	folded["data"] = data
+	return folded
+}
+
+// getHelmDriftedFields compares the fields set in the expected object with the live object and returns the leaf fields that differ.
+// Fields only present in the live object, e.g. defaulted by the API server or set by controllers, are not considered a drift,
+// nor are the empty maps and arrays of the expected object which are missing from the live object, e.g. `annotations: {}`.
+func getHelmDriftedFields(fieldPath string, expected interface{}, actual interface{}) []helmDriftedField {
+	if isEmptyHelmValue(expected) && isEmptyHelmValue(actual) {
+		return nil
+	}
+
+	var drifted []helmDriftedField
+
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualMap, ok := actual.(map[string]interface{})
+		if !ok {
+			return []helmDriftedField{{Path: fieldPath, Expected: expected, Actual: actual}}
+		}
+		for _, k := range sortedUnionKeys(expectedValue, nil) {
+			childPath := k
+			if fieldPath != "" {
+				childPath = fieldPath + "." + k
+			}
+			drifted = append(drifted, getHelmDriftedFields(childPath, expectedValue[k], actualMap[k])...)
+		}
+	case []interface{}:
+		actualSlice, ok := actual.([]interface{})
+		if !ok || len(actualSlice) != len(expectedValue) {
+			return []helmDriftedField{{Path: fieldPath, Expected: expected, Actual: actual}}
+		}
+		for i := range expectedValue {
+			drifted = append(drifted, getHelmDriftedFields(fmt.Sprintf("%s[%d]", fieldPath, i), expectedValue[i], actualSlice[i])...)
+		}
+	default:
+		if !helmValuesEqual(expected, actual) {
+			drifted = append(drifted, helmDriftedField{Path: fieldPath, Expected: expected, Actual: actual})
+		}
+	}
+
+	return drifted
+}
+
+// isEmptyHelmValue returns true if the given value is null, or an empty map or array
+func isEmptyHelmValue(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(value) == 0
+	case []interface{}:
+		return len(value) == 0
+	}
+	return false
+}
+
+// helmValuesEqual compares the given scalar values, treating the numbers of different types as equal if they have the same value.
+// The strings are compared as quantities if both values can be parsed as such, as the API server returns the quantities in their
+// canonical form, e.g. `cpu: 0.5` in the manifest is returned as "500m".
+func helmValuesEqual(a interface{}, b interface{}) bool {
+	aNumber, aOk := toFloat64(a)
+	bNumber, bOk := toFloat64(b)
+	if aOk && bOk {
+		return aNumber == bNumber
+	}
+
+	_, aString := a.(string)
+	_, bString := b.(string)
+	if aString || bString {
+		aQuantity, aOk := toQuantity(a)
+		bQuantity, bOk := toQuantity(b)
+		if aOk && bOk {
+			return aQuantity.Cmp(bQuantity) == 0
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toQuantity(v interface{}) (resource.Quantity, bool) {
+	str, ok := v.(string)
+	if !ok {
+		n, ok := toFloat64(v)
+		if !ok {
+			return resource.Quantity{}, false
+		}
+		str = strconv.FormatFloat(n, 'f', -1, 64)
+	}
+
+	quantity, err := resource.ParseQuantity(str)
+	if err != nil {
+		return resource.Quantity{}, false
+	}
+	return quantity, true
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+//// TRANSFORM FUNCTIONS
+
+func helmReleaseObjectValue(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	o := d.HydrateItem.(helmReleaseObject)
+
+	switch d.Param.(string) {
+	case "APIVersion":
+		return o.Object.GetAPIVersion(), nil
+	case "Kind":
+		return o.Object.GetKind(), nil
+	case "Name":
+		return o.Object.GetName(), nil
+	case "Namespace":
+		return o.Object.GetNamespace(), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,196 @@
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetHelmDriftedFields(t *testing.T) {
+	expected := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web"},
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "web", "image": "nginx:1.25"},
+					},
+				},
+			},
+			"paused": false,
+		},
+	}
+	live := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web", "uid": "1234", "generation": int64(3)},
+		"spec": map[string]interface{}{
+			"replicas": float64(5),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "web", "image": "nginx:1.26", "imagePullPolicy": "IfNotPresent"},
+					},
+				},
+			},
+		},
+	}
+
+	want := []helmDriftedField{
+		{Path: "spec.paused", Expected: false, Actual: nil},
+		{Path: "spec.replicas", Expected: int64(2), Actual: float64(5)},
+		{Path: "spec.template.spec.containers[0].image", Expected: "nginx:1.25", Actual: "nginx:1.26"},
+	}
+
+	got := getHelmDriftedFields("", expected, live)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getHelmDriftedFields() mismatch\n  got:  %+v\n  want: %+v", got, want)
+	}
+
+	if drifted := getHelmDriftedFields("", expected, expected); len(drifted) != 0 {
+		t.Errorf("expected no drift for identical objects, got %+v", drifted)
+	}
+}
+
+func TestGetHelmDriftedFieldsNormalization(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected map[string]interface{}
+		live     map[string]interface{}
+		want     []helmDriftedField
+	}{
+		{
+			name:     "integer quantity",
+			expected: map[string]interface{}{"resources": map[string]interface{}{"limits": map[string]interface{}{"cpu": int64(1)}}},
+			live:     map[string]interface{}{"resources": map[string]interface{}{"limits": map[string]interface{}{"cpu": "1"}}},
+		},
+		{
+			name:     "canonical quantity",
+			expected: map[string]interface{}{"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": float64(0.5), "memory": "1024Mi"}}},
+			live:     map[string]interface{}{"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "500m", "memory": "1Gi"}}},
+		},
+		{
+			name:     "changed quantity",
+			expected: map[string]interface{}{"resources": map[string]interface{}{"limits": map[string]interface{}{"cpu": float64(0.5)}}},
+			live:     map[string]interface{}{"resources": map[string]interface{}{"limits": map[string]interface{}{"cpu": "1"}}},
+			want:     []helmDriftedField{{Path: "resources.limits.cpu", Expected: float64(0.5), Actual: "1"}},
+		},
+		{
+			name:     "empty map and array",
+			expected: map[string]interface{}{"metadata": map[string]interface{}{"annotations": map[string]interface{}{}}, "args": []interface{}{}},
+			live:     map[string]interface{}{"metadata": map[string]interface{}{"name": "web"}},
+		},
+		{
+			name:     "non-empty map",
+			expected: map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "web"}}},
+			live:     map[string]interface{}{"metadata": map[string]interface{}{}},
+			want:     []helmDriftedField{{Path: "metadata.labels", Expected: map[string]interface{}{"app": "web"}, Actual: nil}},
+		},
+		{
+			name: "secret string data",
+			expected: foldHelmSecretStringData(map[string]interface{}{
+				"data":       map[string]interface{}{"username": "YWRtaW4=", "password": "b2xk"},
+				"stringData": map[string]interface{}{"password": "hunter2"},
+			}),
+			live: map[string]interface{}{"data": map[string]interface{}{"username": "YWRtaW4=", "password": "aHVudGVyMg=="}},
+		},
+		{
+			name:     "changed secret string data",
+			expected: foldHelmSecretStringData(map[string]interface{}{"stringData": map[string]interface{}{"password": "hunter2"}}),
+			live:     map[string]interface{}{"data": map[string]interface{}{"password": "aHVudGVyMw=="}},
+			want:     []helmDriftedField{{Path: "data.password", Expected: "aHVudGVyMg==", Actual: "aHVudGVyMw=="}},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := getHelmDriftedFields("", tt.expected, tt.live); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("getHelmDriftedFields(%s) mismatch\n  got:  %+v\n  want: %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFoldHelmSecretStringData(t *testing.T) {
+	obj := map[string]interface{}{
+		"kind":       "Secret",
+		"data":       map[string]interface{}{"password": "b2xk"},
+		"stringData": map[string]interface{}{"password": "hunter2"},
+	}
+
+	want := map[string]interface{}{
+		"kind": "Secret",
+		"data": map[string]interface{}{"password": "aHVudGVyMg=="},
+	}
+	if got := foldHelmSecretStringData(obj); !reflect.DeepEqual(got, want) {
+		t.Errorf("foldHelmSecretStringData() = %v, expected %v", got, want)
+	}
+
+	// The objects of the releases are not modified
+	if _, ok := obj["stringData"]; !ok || obj["data"].(map[string]interface{})["password"] != "b2xk" {
+		t.Errorf("foldHelmSecretStringData() modified the given object: %v", obj)
+	}
+}
+
+func TestApplySecretDataModeToDriftedFields(t *testing.T) {
+	expected := map[string]interface{}{
+		"data":       map[string]interface{}{"password": "aHVudGVyMg==", "tls.crt": "Y2VydA=="},
+		"stringData": map[string]interface{}{"token": "hunter2"},
+		"type":       "Opaque",
+	}
+	live := map[string]interface{}{
+		"data": map[string]interface{}{"password": "aHVudGVyMw==", "tls.crt": "Y2VydA=="},
+		"type": "kubernetes.io/tls",
+	}
+
+	want := []helmDriftedField{
+		{Path: "data.password", Expected: redactedValue, Actual: redactedValue},
+		{Path: "data.token", Expected: redactedValue, Actual: nil},
+		{Path: "type", Expected: "Opaque", Actual: "kubernetes.io/tls"},
+	}
+
+	got := applySecretDataModeToDriftedFields(getHelmDriftedFields("", foldHelmSecretStringData(expected), live), SecretDataModeRedacted)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("applySecretDataModeToDriftedFields() mismatch\n  got:  %+v\n  want: %+v", got, want)
+	}
+
+	// The data of the manifests is not modified
+	if expected["data"].(map[string]interface{})["password"] != "aHVudGVyMg==" {
+		t.Errorf("applySecretDataModeToDriftedFields() modified the expected object: %v", expected)
+	}
+}
+
+func TestHelmDriftedFieldKeys(t *testing.T) {
+	tests := map[string][]string{
+		"data.tls.crt":     {"data", "tls.crt"},
+		"stringData.token": {"stringData", "token"},
+		"metadata.annotations.kubectl.kubernetes.io/last-applied-configuration": {"metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration"},
+		"spec.replicas": {"spec", "replicas"},
+	}
+	for fieldPath, expected := range tests {
+		if got := helmDriftedFieldKeys(fieldPath); !reflect.DeepEqual(got, expected) {
+			t.Errorf("helmDriftedFieldKeys(%q) = %v, expected %v", fieldPath, got, expected)
+		}
+	}
+}
+
+func TestHelmValuesEqual(t *testing.T) {
+	tests := []struct {
+		a, b     interface{}
+		expected bool
+	}{
+		{int64(80), float64(80), true},
+		{int64(80), int64(8080), false},
+		{"80", int64(80), true},
+		{"http", int64(80), false},
+		{int64(1), "1", true},
+		{float64(0.5), "500m", true},
+		{"1024Mi", "1Gi", true},
+		{"500m", "1", false},
+		{"nginx:1.25", "nginx:1.26", false},
+		{true, true, true},
+		{nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		if got := helmValuesEqual(tt.a, tt.b); got != tt.expected {
+			t.Errorf("helmValuesEqual(%v, %v) = %v, expected %v", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableHelmReleaseRevisionDiff(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "helm_release_revision_diff",
+		Description: "List the differences between the manifests and values of two revisions of a release",
+		List: &plugin.ListConfig{
+			Hydrate: listHelmReleaseRevisionDiffs,
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "name", Require: plugin.Required},
+				{Name: "namespace", Require: plugin.Required},
+				{Name: "from_revision", Require: plugin.Optional},
+				{Name: "to_revision", Require: plugin.Optional},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "The name of the release."},
+			{Name: "namespace", Type: proto.ColumnType_STRING, Description: "The kubernetes namespace of the release."},
+			{Name: "from_revision", Type: proto.ColumnType_INT, Description: "The revision to compare from. Defaults to the revision preceding to_revision."},
+			{Name: "to_revision", Type: proto.ColumnType_INT, Description: "The revision to compare to. Defaults to the latest revision."},
+			{Name: "source", Type: proto.ColumnType_STRING, Description: "The part of the release that differs. Possible values: manifest, values."},
+			{Name: "change_type", Type: proto.ColumnType_STRING, Description: "The type of the change. Possible values: added, removed, modified."},
+			{Name: "api_version", Type: proto.ColumnType_STRING, Description: "The API version of the changed object in the manifest.", Transform: transform.FromField("APIVersion").Transform(transform.NullIfZeroValue)},
+			{Name: "kind", Type: proto.ColumnType_STRING, Description: "The kind of the changed object in the manifest.", Transform: transform.FromField("Kind").Transform(transform.NullIfZeroValue)},
+			{Name: "object_name", Type: proto.ColumnType_STRING, Description: "The name of the changed object in the manifest.", Transform: transform.FromField("ObjectName").Transform(transform.NullIfZeroValue)},
+			{Name: "object_namespace", Type: proto.ColumnType_STRING, Description: "The namespace of the changed object in the manifest.", Transform: transform.FromField("ObjectNamespace").Transform(transform.NullIfZeroValue)},
+			{Name: "field_path", Type: proto.ColumnType_STRING, Description: "The path of the changed field, e.g. spec.template.spec.containers[0].image. Null if the whole object is added or removed.", Transform: transform.FromField("FieldPath").Transform(transform.NullIfZeroValue)},
+			{Name: "from_value", Type: proto.ColumnType_JSON, Description: "The value in the from_revision."},
+			{Name: "to_value", Type: proto.ColumnType_JSON, Description: "The value in the to_revision."},
+		},
+	}
+}
+
+type helmReleaseRevisionDiff struct {
+	Name            string
+	Namespace       string
+	FromRevision    int
+	ToRevision      int
+	Source          string
+	ChangeType      string
+	APIVersion      string
+	Kind            string
+	ObjectName      string
+	ObjectNamespace string
+	FieldPath       string
+	FromValue       interface{}
+	ToValue         interface{}
+}
+
+type helmFieldDiff struct {
+	FieldPath  string
+	ChangeType string
+	FromValue  interface{}
+	ToValue    interface{}
+}
+
+//// LIST FUNCTION
+
+func listHelmReleaseRevisionDiffs(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	name := d.EqualsQualString("name")
+	namespace := d.EqualsQualString("namespace")
+
+	// Return nil, if empty
+	if name == "" || namespace == "" {
+		return nil, nil
+	}
+
+	revisions, err := listHelmReleaseRevisions(ctx, d, namespace, name)
+	if err != nil {
+		plugin.Logger(ctx).Error("listHelmReleaseRevisionDiffs", "storage_error", err)
+		return nil, err
+	}
+
+	// Revisions are sorted in ascending order
+	var from, to *HelmRelease
+	toRevision := int(d.EqualsQuals["to_revision"].GetInt64Value())
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if toRevision == 0 || revisions[i].Version == toRevision {
+			to = &revisions[i]
+			break
+		}
+	}
+	if to == nil {
+		return nil, nil
+	}
+
+	fromRevision := int(d.EqualsQuals["from_revision"].GetInt64Value())
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if (fromRevision == 0 && revisions[i].Version < to.Version) || (fromRevision != 0 && revisions[i].Version == fromRevision) {
+			from = &revisions[i]
+			break
+		}
+	}
+	if from == nil {
+		return nil, nil
+	}
+
+	diffs, err := diffHelmReleaseRevisions(from, to)
+	if err != nil {
+		plugin.Logger(ctx).Error("listHelmReleaseRevisionDiffs", "diff_error", err)
+		return nil, err
+	}
+
+	for _, diff := range diffs {
+		d.StreamListItem(ctx, diff)
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// diffHelmReleaseRevisions returns the per-object and per-field differences between the manifests of the given revisions,
+// followed by the differences between their values
+func diffHelmReleaseRevisions(from *HelmRelease, to *HelmRelease) ([]helmReleaseRevisionDiff, error) {
+	newDiff := func(source string) helmReleaseRevisionDiff {
+		return helmReleaseRevisionDiff{
+			Name:         to.Name,
+			Namespace:    to.Namespace,
+			FromRevision: from.Version,
+			ToRevision:   to.Version,
+			Source:       source,
+		}
+	}
+
+	fromObjects, err := parseHelmManifestObjects(from.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest of revision %d: %v", from.Version, err)
+	}
+	toObjects, err := parseHelmManifestObjects(to.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest of revision %d: %v", to.Version, err)
+	}
+
+	var diffs []helmReleaseRevisionDiff
+	for _, key := range sortedUnionKeys(fromObjects, toObjects) {
+		fromObject, fromOk := fromObjects[key].(map[string]interface{})
+		toObject, toOk := toObjects[key].(map[string]interface{})
+
+		object := toObject
+		if !toOk {
+			object = fromObject
+		}
+
+		diff := newDiff("manifest")
+		diff.APIVersion, _ = object["apiVersion"].(string)
+		diff.Kind, _ = object["kind"].(string)
+		if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+			diff.ObjectName, _ = metadata["name"].(string)
+			diff.ObjectNamespace, _ = metadata["namespace"].(string)
+		}
+
+		switch {
+		case !fromOk:
+			diff.ChangeType = "added"
+			diff.ToValue = toObject
+			diffs = append(diffs, diff)
+		case !toOk:
+			diff.ChangeType = "removed"
+			diff.FromValue = fromObject
+			diffs = append(diffs, diff)
+		default:
+			for _, fieldDiff := range diffHelmValues("", fromObject, toObject) {
+				diff.ChangeType = fieldDiff.ChangeType
+				diff.FieldPath = fieldDiff.FieldPath
+				diff.FromValue = fieldDiff.FromValue
+				diff.ToValue = fieldDiff.ToValue
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+
+	for _, fieldDiff := range diffHelmValues("", from.Config, to.Config) {
+		diff := newDiff("values")
+		diff.ChangeType = fieldDiff.ChangeType
+		diff.FieldPath = fieldDiff.FieldPath
+		diff.FromValue = fieldDiff.FromValue
+		diff.ToValue = fieldDiff.ToValue
+		diffs = append(diffs, diff)
+	}
+
+	return diffs, nil
+}
+
+// parseHelmManifestObjects splits the rendered manifest of a release and returns the objects keyed by their kind, namespace and name
+func parseHelmManifestObjects(manifest string) (map[string]interface{}, error) {
+	objects := map[string]interface{}{}
+
+	for _, content := range yamlDocSeparator.Split(manifest, -1) {
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+
+		var object map[string]interface{}
+		if err := yaml.Unmarshal([]byte(content), &object); err != nil {
+			return nil, err
+		}
+
+		// Skip the documents containing only comments
+		if len(object) == 0 {
+			continue
+		}
+
+		var name, namespace string
+		if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+			name, _ = metadata["name"].(string)
+			namespace, _ = metadata["namespace"].(string)
+		}
+		objects[fmt.Sprintf("%v/%s/%s", object["kind"], namespace, name)] = object
+	}
+
+	return objects, nil
+}
+
+// diffHelmValues recursively compares the given values and returns the differences of the leaf fields
+func diffHelmValues(fieldPath string, from interface{}, to interface{}) []helmFieldDiff {
+	var diffs []helmFieldDiff
+
+	fromMap, fromIsMap := from.(map[string]interface{})
+	toMap, toIsMap := to.(map[string]interface{})
+	fromSlice, fromIsSlice := from.([]interface{})
+	toSlice, toIsSlice := to.([]interface{})
+
+	switch {
+	case (fromIsMap || from == nil) && (toIsMap || to == nil) && !(from == nil && to == nil):
+		for _, k := range sortedUnionKeys(fromMap, toMap) {
+			childPath := k
+			if fieldPath != "" {
+				childPath = fieldPath + "." + k
+			}
+
+			fromValue, fromOk := fromMap[k]
+			toValue, toOk := toMap[k]
+			switch {
+			case !fromOk:
+				diffs = append(diffs, helmFieldDiff{FieldPath: childPath, ChangeType: "added", ToValue: toValue})
+			case !toOk:
+				diffs = append(diffs, helmFieldDiff{FieldPath: childPath, ChangeType: "removed", FromValue: fromValue})
+			default:
+				diffs = append(diffs, diffHelmValues(childPath, fromValue, toValue)...)
+			}
+		}
+	case fromIsSlice && toIsSlice:
+		for i := 0; i < len(fromSlice) || i < len(toSlice); i++ {
+			childPath := fmt.Sprintf("%s[%d]", fieldPath, i)
+			switch {
+			case i >= len(fromSlice):
+				diffs = append(diffs, helmFieldDiff{FieldPath: childPath, ChangeType: "added", ToValue: toSlice[i]})
+			case i >= len(toSlice):
+				diffs = append(diffs, helmFieldDiff{FieldPath: childPath, ChangeType: "removed", FromValue: fromSlice[i]})
+			default:
+				diffs = append(diffs, diffHelmValues(childPath, fromSlice[i], toSlice[i])...)
+			}
+		}
+	case !reflect.DeepEqual(from, to):
+		diffs = append(diffs, helmFieldDiff{FieldPath: fieldPath, ChangeType: "modified", FromValue: from, ToValue: to})
+	}
+
+	return diffs
+}
+
+// sortedUnionKeys returns the sorted union of the keys of the given maps
+func sortedUnionKeys(a map[string]interface{}, b map[string]interface{}) []string {
+	keys := []string{}
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
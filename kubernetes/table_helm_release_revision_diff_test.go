@@ -0,0 +1,66 @@
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffHelmValues(t *testing.T) {
+	from := map[string]interface{}{
+		"replicaCount": int64(1),
+		"image":        map[string]interface{}{"tag": "1.0"},
+		"ports":        []interface{}{int64(80), int64(443)},
+		"debug":        true,
+	}
+	to := map[string]interface{}{
+		"replicaCount": int64(3),
+		"image":        map[string]interface{}{"tag": "1.0", "pullPolicy": "Always"},
+		"ports":        []interface{}{int64(8080)},
+	}
+
+	expected := []helmFieldDiff{
+		{FieldPath: "debug", ChangeType: "removed", FromValue: true},
+		{FieldPath: "image.pullPolicy", ChangeType: "added", ToValue: "Always"},
+		{FieldPath: "ports[0]", ChangeType: "modified", FromValue: int64(80), ToValue: int64(8080)},
+		{FieldPath: "ports[1]", ChangeType: "removed", FromValue: int64(443)},
+		{FieldPath: "replicaCount", ChangeType: "modified", FromValue: int64(1), ToValue: int64(3)},
+	}
+
+	got := diffHelmValues("", from, to)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("diffHelmValues() mismatch\n  got:      %+v\n  expected: %+v", got, expected)
+	}
+
+	if diffs := diffHelmValues("", from, from); len(diffs) != 0 {
+		t.Errorf("expected no differences for identical values, got %+v", diffs)
+	}
+}
+
+func TestParseHelmManifestObjects(t *testing.T) {
+	manifest := `---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+  namespace: default
+---
+# Source: app/templates/empty.yaml
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+`
+
+	objects, err := parseHelmManifestObjects(manifest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keys := sortedUnionKeys(objects, nil)
+	expected := []string{"Deployment//app", "Service/default/app"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("parseHelmManifestObjects() keys mismatch\n  got:      %v\n  expected: %v", keys, expected)
+	}
+}
@@ -0,0 +1,61 @@
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableHelmValueSchema(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "helm_value_schema",
+		Description: "Lists the properties defined in the values.schema.json file of the configured charts and their subcharts",
+		List: &plugin.ListConfig{
+			Hydrate: listHelmValueSchemas,
+		},
+		Columns: []*plugin.Column{
+			{Name: "chart_name", Type: proto.ColumnType_STRING, Description: "The name of the chart defining the schema."},
+			{Name: "path", Type: proto.ColumnType_STRING, Description: "The path to the schema file."},
+			{Name: "key_path", Type: proto.ColumnType_LTREE, Transform: transform.FromField("Key").Transform(keysToSnakeCase), Description: "Specifies full path of the property in the values. Subchart properties are prefixed with the subchart name."},
+			{Name: "keys", Type: proto.ColumnType_JSON, Transform: transform.FromField("Key"), Description: "The array representation of path of the property. Array items are represented by \"*\"."},
+			{Name: "type", Type: proto.ColumnType_STRING, Description: "The type of the property. Multiple types are separated by a comma."},
+			{Name: "format", Type: proto.ColumnType_STRING, Description: "The format of the property, e.g. date-time.", Transform: transform.FromField("Format").Transform(transform.NullIfZeroValue)},
+			{Name: "required", Type: proto.ColumnType_BOOL, Description: "Indicates whether the property is required by its parent."},
+			{Name: "default", Type: proto.ColumnType_JSON, Description: "The default value of the property."},
+			{Name: "enum", Type: proto.ColumnType_JSON, Description: "The list of allowed values of the property."},
+			{Name: "description", Type: proto.ColumnType_STRING, Description: "The description of the property.", Transform: transform.FromField("Description").Transform(transform.NullIfZeroValue)},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listHelmValueSchemas(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	charts, err := getUniqueHelmCharts(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, chart := range charts {
+		properties, err := getHelmValueSchemaProperties(chart.Chart, chart.Path, chart.Chart, nil)
+		if err != nil {
+			plugin.Logger(ctx).Error("helm_value_schema.listHelmValueSchemas", "parse_error", err, "path", chart.Path)
+			return nil, err
+		}
+
+		for _, property := range properties {
+			d.StreamListItem(ctx, property)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,55 @@
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableHelmValueSchemaViolation(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "helm_value_schema_violation",
+		Description: "Lists the values of the configured charts that violate the values.schema.json of the chart or its subcharts",
+		List: &plugin.ListConfig{
+			Hydrate: listHelmValueSchemaViolations,
+		},
+		Columns: []*plugin.Column{
+			{Name: "chart_name", Type: proto.ColumnType_STRING, Description: "The name of the chart whose schema is violated."},
+			{Name: "chart_path", Type: proto.ColumnType_STRING, Description: "The path to the directory where the configured chart is located."},
+			{Name: "source_type", Type: proto.ColumnType_STRING, Description: "The configured chart render the values belong to."},
+			{Name: "key_path", Type: proto.ColumnType_LTREE, Transform: transform.FromField("Key").Transform(keysToSnakeCase), Description: "Specifies full path of the violating key in the values."},
+			{Name: "keys", Type: proto.ColumnType_JSON, Transform: transform.FromField("Key"), Description: "The array representation of path of the violating key."},
+			{Name: "error_type", Type: proto.ColumnType_STRING, Description: "The type of the violation, e.g. required, invalid_type, enum."},
+			{Name: "message", Type: proto.ColumnType_STRING, Description: "The description of the violation."},
+			{Name: "value", Type: proto.ColumnType_JSON, Description: "The value violating the schema."},
+			{Name: "path", Type: proto.ColumnType_STRING, Description: "The path to the values file where the key is defined.", Transform: transform.FromField("Path").Transform(transform.NullIfZeroValue)},
+			{Name: "start_line", Type: proto.ColumnType_INT, Description: "Specifies the line number where the key is defined in the values file. For the missing keys, the line of the closest defined parent key is used.", Transform: transform.FromField("StartLine").NullIfZero()},
+			{Name: "start_column", Type: proto.ColumnType_INT, Description: "Specifies the starting column of the value.", Transform: transform.FromField("StartColumn").NullIfZero()},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listHelmValueSchemaViolations(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	violations, err := getHelmValueSchemaViolations(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("helm_value_schema_violation.listHelmValueSchemaViolations", "validation_error", err)
+		return nil, err
+	}
+
+	for _, violation := range violations {
+		d.StreamListItem(ctx, violation)
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
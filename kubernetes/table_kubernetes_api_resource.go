@@ -0,0 +1,154 @@
+package kubernetes
+
+import (
+	"context"
+	"strings"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+func tableKubernetesAPIResource(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "kubernetes_api_resource",
+		Description: "Kubernetes API resources served by the cluster, as reported by the API discovery.",
+		List: &plugin.ListConfig{
+			Hydrate: listK8sAPIResources,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "group", Require: plugin.Optional},
+				{Name: "name", Require: plugin.Optional},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "The plural name of the resource, e.g. deployments."},
+			{Name: "singular_name", Type: proto.ColumnType_STRING, Description: "The singular name of the resource, e.g. deployment.", Transform: transform.FromField("SingularName").Transform(transform.NullIfZeroValue)},
+			{Name: "kind", Type: proto.ColumnType_STRING, Description: "The kind of the resource, e.g. Deployment."},
+			{Name: "group", Type: proto.ColumnType_STRING, Description: "The API group of the resource. Empty for the core group."},
+			{Name: "version", Type: proto.ColumnType_STRING, Description: "The API version of the resource, without the group, e.g. v1."},
+			{Name: "api_version", Type: proto.ColumnType_STRING, Description: "The API version of the resource, including the group, e.g. apps/v1.", Transform: transform.FromField("APIVersion")},
+			{Name: "preferred_version", Type: proto.ColumnType_BOOL, Description: "True if the version is the preferred version of the API group."},
+			{Name: "namespaced", Type: proto.ColumnType_BOOL, Description: "True if the resource is namespace scoped."},
+			{Name: "verbs", Type: proto.ColumnType_JSON, Description: "The verbs supported by the resource, e.g. get, list and watch."},
+			{Name: "short_names", Type: proto.ColumnType_JSON, Description: "The short names of the resource, e.g. deploy."},
+			{Name: "categories", Type: proto.ColumnType_JSON, Description: "The categories the resource belongs to, e.g. all."},
+			{Name: "context_name", Type: proto.ColumnType_STRING, Description: "Kubectl config context name.", Transform: transform.FromField("ContextName").Transform(transform.NullIfZeroValue)},
+		},
+	}
+}
+
+type APIResource struct {
+	Name             string
+	SingularName     string
+	Kind             string
+	Group            string
+	Version          string
+	APIVersion       string
+	PreferredVersion bool
+	Namespaced       bool
+	Verbs            []string
+	ShortNames       []string
+	Categories       []string
+	ContextName      string
+}
+
+//// HYDRATE FUNCTIONS
+
+func listK8sAPIResources(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	resources, err := getK8sAPIResources(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("listK8sAPIResources", "discovery_error", err)
+		return nil, err
+	}
+
+	currentContext, _ := getCurrentContext(ctx, d, nil).(string)
+
+	for _, resource := range resources {
+		if d.EqualsQuals["group"] != nil && d.EqualsQualString("group") != resource.Group {
+			continue
+		}
+		if d.EqualsQualString("name") != "" && d.EqualsQualString("name") != resource.Name {
+			continue
+		}
+
+		resource.ContextName = currentContext
+		d.StreamListItem(ctx, resource)
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// getK8sAPIResources returns the resources of all the API groups and versions served by the cluster.
+// The groups which cannot be discovered, e.g. an aggregated API whose backing service is down, are skipped.
+func getK8sAPIResources(ctx context.Context, d *plugin.QueryData) ([]APIResource, error) {
+	client, err := getDiscoveryClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// Discovery is only available for the deployed resources
+	if client == nil {
+		return nil, nil
+	}
+
+	groups, resourceLists, err := client.ServerGroupsAndResources()
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, err
+		}
+		plugin.Logger(ctx).Warn("getK8sAPIResources", "partial_discovery_error", err)
+	}
+
+	return newAPIResources(groups, resourceLists), nil
+}
+
+// newAPIResources flattens the discovered resource lists into one item per resource and version.
+// Subresources, e.g. pods/log, are not listable on their own and are skipped.
+func newAPIResources(groups []*metav1.APIGroup, resourceLists []*metav1.APIResourceList) []APIResource {
+	preferredVersions := map[string]string{}
+	for _, group := range groups {
+		preferredVersions[group.Name] = group.PreferredVersion.Version
+	}
+
+	var resources []APIResource
+	for _, resourceList := range resourceLists {
+		if resourceList == nil {
+			continue
+		}
+
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") {
+				continue
+			}
+
+			resources = append(resources, APIResource{
+				Name:             resource.Name,
+				SingularName:     resource.SingularName,
+				Kind:             resource.Kind,
+				Group:            gv.Group,
+				Version:          gv.Version,
+				APIVersion:       gv.String(),
+				PreferredVersion: preferredVersions[gv.Group] == gv.Version,
+				Namespaced:       resource.Namespaced,
+				Verbs:            resource.Verbs,
+				ShortNames:       resource.ShortNames,
+				Categories:       resource.Categories,
+			})
+		}
+	}
+
+	return resources
+}
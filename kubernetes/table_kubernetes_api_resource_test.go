@@ -0,0 +1,41 @@
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewAPIResources(t *testing.T) {
+	groups := []*metav1.APIGroup{
+		{Name: "", PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "v1", Version: "v1"}},
+		{Name: "autoscaling", PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "autoscaling/v2", Version: "v2"}},
+	}
+	resourceLists := []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", SingularName: "pod", Kind: "Pod", Namespaced: true, Verbs: []string{"get", "list"}, ShortNames: []string{"po"}, Categories: []string{"all"}},
+				{Name: "pods/log", Kind: "Pod", Namespaced: true, Verbs: []string{"get"}},
+			},
+		},
+		{
+			GroupVersion: "autoscaling/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "horizontalpodautoscalers", Kind: "HorizontalPodAutoscaler", Namespaced: true, Verbs: []string{"list"}},
+			},
+		},
+		nil,
+	}
+
+	expected := []APIResource{
+		{Name: "pods", SingularName: "pod", Kind: "Pod", Group: "", Version: "v1", APIVersion: "v1", PreferredVersion: true, Namespaced: true, Verbs: []string{"get", "list"}, ShortNames: []string{"po"}, Categories: []string{"all"}},
+		{Name: "horizontalpodautoscalers", Kind: "HorizontalPodAutoscaler", Group: "autoscaling", Version: "v1", APIVersion: "autoscaling/v1", PreferredVersion: false, Namespaced: true, Verbs: []string{"list"}},
+	}
+
+	got := newAPIResources(groups, resourceLists)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("newAPIResources() mismatch\n  got:  %+v\n  want: %+v", got, expected)
+	}
+}
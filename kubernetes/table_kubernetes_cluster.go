@@ -0,0 +1,226 @@
+package kubernetes
+
+import (
+	"context"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+//// TABLE DEFINITION
+
+func tableKubernetesCluster(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "kubernetes_cluster",
+		Description: "Version and API server info of the cluster, e.g. the server version, the API groups served and the kubelet versions of the nodes.",
+		List: &plugin.ListConfig{
+			Hydrate: listK8sClusters,
+		},
+		Columns: []*plugin.Column{
+			{Name: "git_version", Type: proto.ColumnType_STRING, Description: "The version of the API server, e.g. v1.30.2 or v1.29.4-eks-036c24b."},
+			{Name: "major", Type: proto.ColumnType_STRING, Description: "The major version of the API server, e.g. 1."},
+			{Name: "minor", Type: proto.ColumnType_STRING, Description: "The minor version of the API server, e.g. 30. Some providers add a suffix, e.g. 29+."},
+			{Name: "platform", Type: proto.ColumnType_STRING, Description: "The OS and architecture the API server runs on, e.g. linux/amd64."},
+			{Name: "build_date", Type: proto.ColumnType_TIMESTAMP, Description: "The build date of the API server."},
+			{Name: "go_version", Type: proto.ColumnType_STRING, Description: "The Go version the API server is built with."},
+			{Name: "git_commit", Type: proto.ColumnType_STRING, Description: "The commit the API server is built from."},
+			{Name: "uid", Type: proto.ColumnType_STRING, Hydrate: getK8sClusterUID, Description: "The UID of the kube-system namespace, which identifies the cluster across its contexts and API server URLs.", Transform: transform.FromValue()},
+
+			// Discovery
+			{Name: "api_groups", Type: proto.ColumnType_JSON, Hydrate: getK8sClusterAPIGroups, Description: "The API groups served by the cluster, along with their versions and preferred version.", Transform: transform.FromField("APIGroups")},
+			{Name: "api_versions", Type: proto.ColumnType_JSON, Hydrate: getK8sClusterAPIGroups, Description: "The API versions served by the cluster, including the group, e.g. apps/v1.", Transform: transform.FromField("APIVersions")},
+
+			// Nodes
+			{Name: "node_count", Type: proto.ColumnType_INT, Hydrate: getK8sClusterNodes, Description: "The number of nodes of the cluster.", Transform: transform.FromField("NodeCount")},
+			{Name: "kubelet_versions", Type: proto.ColumnType_JSON, Hydrate: getK8sClusterNodes, Description: "The kubelet versions of the nodes, along with the number of nodes running each version.", Transform: transform.FromField("KubeletVersions")},
+			{Name: "max_kubelet_version_skew", Type: proto.ColumnType_INT, Hydrate: getK8sClusterNodes, Description: "The largest number of minor versions the kubelets are behind the API server, e.g. 2 for a v1.28 kubelet with a v1.30 API server. Negative if a kubelet is ahead of the API server.", Transform: transform.FromField("MaxKubeletVersionSkew")},
+
+			{Name: "context_name", Type: proto.ColumnType_STRING, Description: "Kubectl config context name.", Transform: transform.FromField("ContextName").Transform(transform.NullIfZeroValue)},
+		},
+	}
+}
+
+type Cluster struct {
+	GitVersion  string
+	Major       string
+	Minor       string
+	Platform    string
+	BuildDate   *time.Time
+	GoVersion   string
+	GitCommit   string
+	ContextName string
+}
+
+type ClusterAPIGroups struct {
+	APIGroups   []ClusterAPIGroup
+	APIVersions []string
+}
+
+type ClusterAPIGroup struct {
+	Name             string   `json:"name"`
+	Versions         []string `json:"versions"`
+	PreferredVersion string   `json:"preferred_version"`
+}
+
+type ClusterNodes struct {
+	NodeCount             int
+	KubeletVersions       map[string]int
+	MaxKubeletVersionSkew *int
+}
+
+//// LIST FUNCTION
+
+func listK8sClusters(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	client, err := getDiscoveryClient(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("listK8sClusters", "client_error", err)
+		return nil, err
+	}
+
+	// The cluster is only available for the deployed resources
+	if client == nil {
+		return nil, nil
+	}
+
+	serverVersion, err := client.ServerVersion()
+	if err != nil {
+		plugin.Logger(ctx).Error("listK8sClusters", "server_version_error", err)
+		return nil, err
+	}
+
+	cluster := Cluster{
+		GitVersion: serverVersion.GitVersion,
+		Major:      serverVersion.Major,
+		Minor:      serverVersion.Minor,
+		Platform:   serverVersion.Platform,
+		GoVersion:  serverVersion.GoVersion,
+		GitCommit:  serverVersion.GitCommit,
+	}
+	if buildDate, err := time.Parse(time.RFC3339, serverVersion.BuildDate); err == nil {
+		cluster.BuildDate = &buildDate
+	}
+	cluster.ContextName, _ = getCurrentContext(ctx, d, nil).(string)
+
+	d.StreamListItem(ctx, cluster)
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getK8sClusterUID(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	clientset, err := GetNewClientset(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// The kube-system namespace is created with the cluster and cannot be deleted
+	namespace, err := clientset.CoreV1().Namespaces().Get(ctx, metav1.NamespaceSystem, metav1.GetOptions{})
+	if err != nil {
+		plugin.Logger(ctx).Error("getK8sClusterUID", "api_error", err)
+		return nil, err
+	}
+
+	return string(namespace.UID), nil
+}
+
+func getK8sClusterAPIGroups(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	client, err := getDiscoveryClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	groupList, err := client.ServerGroups()
+	if err != nil {
+		plugin.Logger(ctx).Error("getK8sClusterAPIGroups", "discovery_error", err)
+		return nil, err
+	}
+
+	groups := ClusterAPIGroups{
+		APIGroups:   []ClusterAPIGroup{},
+		APIVersions: metav1.ExtractGroupVersions(groupList),
+	}
+	for _, group := range groupList.Groups {
+		apiGroup := ClusterAPIGroup{
+			Name:             group.Name,
+			PreferredVersion: group.PreferredVersion.Version,
+		}
+		for _, groupVersion := range group.Versions {
+			apiGroup.Versions = append(apiGroup.Versions, groupVersion.Version)
+		}
+		groups.APIGroups = append(groups.APIGroups, apiGroup)
+	}
+
+	return groups, nil
+}
+
+func getK8sClusterNodes(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	clientset, err := GetNewClientset(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	input := metav1.ListOptions{
+		Limit: 500,
+	}
+
+	var kubeletVersions []string
+	var response *v1.NodeList
+	pageLeft := true
+
+	for pageLeft {
+		response, err = clientset.CoreV1().Nodes().List(ctx, input)
+		if err != nil {
+			plugin.Logger(ctx).Error("getK8sClusterNodes", "api_error", err)
+			return nil, err
+		}
+
+		if response.GetContinue() != "" {
+			input.Continue = response.Continue
+		} else {
+			pageLeft = false
+		}
+
+		for _, node := range response.Items {
+			kubeletVersions = append(kubeletVersions, node.Status.NodeInfo.KubeletVersion)
+		}
+	}
+
+	nodes := ClusterNodes{
+		NodeCount:       len(kubeletVersions),
+		KubeletVersions: map[string]int{},
+	}
+	for _, kubeletVersion := range kubeletVersions {
+		nodes.KubeletVersions[kubeletVersion]++
+	}
+	nodes.MaxKubeletVersionSkew = getMaxKubeletVersionSkew(h.Item.(Cluster).GitVersion, kubeletVersions)
+
+	return nodes, nil
+}
+
+// getMaxKubeletVersionSkew returns the largest number of minor versions the kubelets are behind the API server.
+// The versions which cannot be parsed, or of another major version, are ignored. Returns nil if no kubelet version is comparable.
+func getMaxKubeletVersionSkew(serverVersion string, kubeletVersions []string) *int {
+	server, err := version.ParseGeneric(serverVersion)
+	if err != nil {
+		return nil
+	}
+
+	var maxSkew *int
+	for _, kubeletVersion := range kubeletVersions {
+		kubelet, err := version.ParseGeneric(kubeletVersion)
+		if err != nil || kubelet.Major() != server.Major() {
+			continue
+		}
+		skew := int(server.Minor()) - int(kubelet.Minor())
+		if maxSkew == nil || skew > *maxSkew {
+			maxSkew = &skew
+		}
+	}
+	return maxSkew
+}
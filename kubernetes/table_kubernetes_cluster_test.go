@@ -0,0 +1,31 @@
+package kubernetes
+
+import (
+	"testing"
+)
+
+func TestGetMaxKubeletVersionSkew(t *testing.T) {
+	tests := []struct {
+		name            string
+		serverVersion   string
+		kubeletVersions []string
+		expected        *int
+	}{
+		{name: "same version", serverVersion: "v1.30.2", kubeletVersions: []string{"v1.30.2", "v1.30.1"}, expected: intPtr(0)},
+		{name: "older kubelets", serverVersion: "v1.30.2", kubeletVersions: []string{"v1.30.2", "v1.28.9", "v1.29.0"}, expected: intPtr(2)},
+		{name: "newer kubelet", serverVersion: "v1.29.0", kubeletVersions: []string{"v1.30.0"}, expected: intPtr(-1)},
+		{name: "provider suffixes", serverVersion: "v1.29.4-eks-036c24b", kubeletVersions: []string{"v1.27.12-eks-ae9a62a"}, expected: intPtr(2)},
+		{name: "no nodes", serverVersion: "v1.30.2", expected: nil},
+		{name: "unparsable", serverVersion: "v1.30.2", kubeletVersions: []string{"unknown"}, expected: nil},
+	}
+	for _, tt := range tests {
+		got := getMaxKubeletVersionSkew(tt.serverVersion, tt.kubeletVersions)
+		if (got == nil) != (tt.expected == nil) || (got != nil && *got != *tt.expected) {
+			t.Errorf("%s: getMaxKubeletVersionSkew() = %v, expected %v", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
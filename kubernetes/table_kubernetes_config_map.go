@@ -136,7 +136,7 @@ func listK8sConfigMaps(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydra
 	pageLeft := true
 
 	for pageLeft {
-		response, err = clientset.CoreV1().ConfigMaps(d.EqualsQualString("namespace")).List(ctx, input)
+		response, err = clientset.CoreV1().ConfigMaps(getListNamespace(ctx, d)).List(ctx, input)
 		if err != nil {
 			return nil, err
 		}
@@ -148,6 +148,11 @@ func listK8sConfigMaps(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydra
 		}
 
 		for _, configMap := range response.Items {
+			// Skip the resources of the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, configMap.Namespace) {
+				continue
+			}
+
 			d.StreamListItem(ctx, ConfigMap{configMap, parsedContent{SourceType: "deployed"}})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -179,6 +184,11 @@ func getK8sConfigMap(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 		return nil, nil
 	}
 
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, namespace) {
+		return nil, nil
+	}
+
 	// Get the manifest resource
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "ConfigMap")
 	if err != nil {
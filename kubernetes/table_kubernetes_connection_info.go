@@ -0,0 +1,309 @@
+package kubernetes
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	"k8s.io/client-go/rest"
+)
+
+//// TABLE DEFINITION
+
+func tableKubernetesConnectionInfo(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "kubernetes_connection_info",
+		Description: "Diagnostics of the connection, i.e. how the cluster and the other sources are resolved, along with the last error of each.",
+		List: &plugin.ListConfig{
+			Hydrate: listK8sConnectionInfo,
+		},
+		Columns: []*plugin.Column{
+			{Name: "source_types", Type: proto.ColumnType_JSON, Description: "The enabled source types, e.g. deployed and manifest."},
+			{Name: "namespaces", Type: proto.ColumnType_JSON, Description: "The namespaces the connection is scoped to, if any."},
+			{Name: "exclude_namespaces", Type: proto.ColumnType_JSON, Description: "The namespaces excluded from the connection, if any."},
+
+			// Cluster
+			{Name: "config_source", Type: proto.ColumnType_STRING, Hydrate: getK8sConnectionClusterInfo, Description: "How the cluster config is resolved, i.e. kubeconfig, inline (the kubeconfig contents set in config_path), explicit (the host and credentials set in the connection config) or in_cluster (the service account Kubernetes gives to pods). Null if the deployed source is disabled.", Transform: transform.FromField("ConfigSource").Transform(transform.NullIfZeroValue)},
+			{Name: "kubeconfig_paths", Type: proto.ColumnType_JSON, Hydrate: getK8sConnectionClusterInfo, Description: "The resolved paths of the kubeconfig files, in their loading order.", Transform: transform.FromField("KubeconfigPaths")},
+			{Name: "context_name", Type: proto.ColumnType_STRING, Hydrate: getK8sConnectionClusterInfo, Description: "Kubectl config context name.", Transform: transform.FromField("ContextName").Transform(transform.NullIfZeroValue)},
+			{Name: "server", Type: proto.ColumnType_STRING, Hydrate: getK8sConnectionClusterInfo, Description: "The URL of the API server.", Transform: transform.FromField("Server").Transform(transform.NullIfZeroValue)},
+			{Name: "auth_method", Type: proto.ColumnType_STRING, Hydrate: getK8sConnectionClusterInfo, Description: "The authentication method, i.e. exec, token, token_file, client_certificate, basic, none, or the name of the auth provider, e.g. oidc.", Transform: transform.FromField("AuthMethod").Transform(transform.NullIfZeroValue)},
+			{Name: "impersonate_user", Type: proto.ColumnType_STRING, Hydrate: getK8sConnectionClusterInfo, Description: "The impersonated user, if any.", Transform: transform.FromField("ImpersonateUser").Transform(transform.NullIfZeroValue)},
+			{Name: "server_version", Type: proto.ColumnType_STRING, Hydrate: getK8sConnectionClusterInfo, Description: "The version of the API server, e.g. v1.30.2.", Transform: transform.FromField("ServerVersion").Transform(transform.NullIfZeroValue)},
+			{Name: "cluster_error", Type: proto.ColumnType_STRING, Hydrate: getK8sConnectionClusterInfo, Description: "The error raised while loading the cluster config or reaching the API server, if any.", Transform: transform.FromField("Error").Transform(transform.NullIfZeroValue)},
+
+			// Files
+			{Name: "manifest_files", Type: proto.ColumnType_JSON, Hydrate: getK8sConnectionManifestInfo, Description: "The manifest files resolved from the manifest_file_paths argument.", Transform: transform.FromField("ManifestFiles")},
+			{Name: "manifest_error", Type: proto.ColumnType_STRING, Hydrate: getK8sConnectionManifestInfo, Description: "The error raised while resolving or parsing the manifest files, if any.", Transform: transform.FromField("ManifestError").Transform(transform.NullIfZeroValue)},
+			{Name: "snapshot_files", Type: proto.ColumnType_JSON, Hydrate: getK8sConnectionManifestInfo, Description: "The snapshot files resolved from the snapshot_paths argument.", Transform: transform.FromField("SnapshotFiles")},
+			{Name: "snapshot_error", Type: proto.ColumnType_STRING, Hydrate: getK8sConnectionManifestInfo, Description: "The error raised while resolving the snapshot files, if any.", Transform: transform.FromField("SnapshotError").Transform(transform.NullIfZeroValue)},
+
+			// Helm
+			{Name: "helm_charts", Type: proto.ColumnType_JSON, Hydrate: getK8sConnectionHelmChartInfo, Description: "The charts configured in the helm_rendered_charts argument, along with their render status and error, if any.", Transform: transform.FromValue()},
+
+			// Custom resources
+			{Name: "custom_resource_tables", Type: proto.ColumnType_JSON, Hydrate: getK8sConnectionCustomResourceInfo, Description: "The CRDs matched by the custom_resource_tables argument, along with the names of their tables.", Transform: transform.FromField("CustomResources")},
+			{Name: "custom_resource_error", Type: proto.ColumnType_STRING, Hydrate: getK8sConnectionCustomResourceInfo, Description: "The error raised while listing the CRDs, if any.", Transform: transform.FromField("Error").Transform(transform.NullIfZeroValue)},
+		},
+	}
+}
+
+type ConnectionInfo struct {
+	SourceTypes       []string
+	Namespaces        []string
+	ExcludeNamespaces []string
+}
+
+type ConnectionClusterInfo struct {
+	ConfigSource    string
+	KubeconfigPaths []string
+	ContextName     string
+	Server          string
+	AuthMethod      string
+	ImpersonateUser string
+	ServerVersion   string
+	Error           string
+}
+
+type ConnectionManifestInfo struct {
+	ManifestFiles []string
+	ManifestError string
+	SnapshotFiles []string
+	SnapshotError string
+}
+
+type ConnectionHelmChartInfo struct {
+	Name            string   `json:"name"`
+	ChartPath       string   `json:"chart_path"`
+	ValuesFilePaths []string `json:"values_file_paths"`
+	Status          string   `json:"status"`
+	Templates       int      `json:"templates"`
+	Error           string   `json:"error,omitempty"`
+}
+
+type ConnectionCustomResourceInfo struct {
+	CustomResources []ConnectionCustomResource
+	Error           string
+}
+
+type ConnectionCustomResource struct {
+	Name     string   `json:"name"`
+	Group    string   `json:"group"`
+	Kind     string   `json:"kind"`
+	Scope    string   `json:"scope"`
+	Versions []string `json:"versions"`
+	Tables   []string `json:"tables"`
+}
+
+//// LIST FUNCTION
+
+func listK8sConnectionInfo(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	kubernetesConfig := GetConfig(d.Connection)
+
+	sources := getSourceTypes(kubernetesConfig)
+
+	d.StreamListItem(ctx, ConnectionInfo{
+		SourceTypes:       sources,
+		Namespaces:        kubernetesConfig.Namespaces,
+		ExcludeNamespaces: kubernetesConfig.ExcludeNamespaces,
+	})
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+// The errors are reported in the columns instead of failing the query, since the table is used to troubleshoot the connection
+
+func getK8sConnectionClusterInfo(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	info := ConnectionClusterInfo{}
+
+	if !slices.Contains(h.Item.(ConnectionInfo).SourceTypes, Deployed.String()) {
+		return info, nil
+	}
+
+	kubernetesConfig := GetConfig(d.Connection)
+	if kubernetesConfig.ImpersonateUser != nil {
+		info.ImpersonateUser = *kubernetesConfig.ImpersonateUser
+	}
+
+	kubeconfig, err := getK8Config(ctx, d)
+	if err != nil {
+		info.Error = err.Error()
+		return info, nil
+	}
+
+	info.ConfigSource = "kubeconfig"
+	if _, ok, _ := tryExplicitClusterConfig(kubernetesConfig); ok {
+		info.ConfigSource = "explicit"
+	} else if kubernetesConfig.ConfigPath != nil {
+		if _, isInline, _ := pathOrContents(*kubernetesConfig.ConfigPath); isInline {
+			info.ConfigSource = "inline"
+		}
+	}
+	if info.ConfigSource == "kubeconfig" {
+		info.KubeconfigPaths = kubeconfig.ConfigAccess().GetLoadingPrecedence()
+	}
+
+	if context, err := getKubectlContext(ctx, d, h); err == nil {
+		info.ContextName, _ = context.(string)
+	}
+
+	// The auth method is read from the config as loaded, i.e. before the exec plugin settings are applied
+	restconfig, err := kubeconfig.ClientConfig()
+	if isMissingKubeconfigError(err) {
+		info.ConfigSource = "in_cluster"
+		restconfig, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		info.Error = err.Error()
+		return info, nil
+	}
+	info.Server = restconfig.Host
+	info.AuthMethod = getAuthMethod(restconfig)
+
+	discoveryClient, err := getDiscoveryClient(ctx, d)
+	if err != nil {
+		info.Error = err.Error()
+		return info, nil
+	}
+	version, err := discoveryClient.ServerVersion()
+	if err != nil {
+		info.Error = err.Error()
+		return info, nil
+	}
+	info.ServerVersion = version.GitVersion
+
+	return info, nil
+}
+
+func getK8sConnectionManifestInfo(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	info := ConnectionManifestInfo{}
+
+	manifestFiles, err := resolveManifestFilePaths(ctx, d)
+	if err != nil {
+		info.ManifestError = err.Error()
+	} else {
+		info.ManifestFiles = manifestFiles
+
+		// The files are resolved, but may not be parsed
+		if _, err := getParsedManifestFileContent(ctx, d); err != nil {
+			info.ManifestError = err.Error()
+		}
+	}
+
+	snapshotFiles, err := resolveSnapshotFilePaths(ctx, d)
+	if err != nil {
+		info.SnapshotError = err.Error()
+	} else {
+		info.SnapshotFiles = snapshotFiles
+	}
+
+	return info, nil
+}
+
+func getK8sConnectionHelmChartInfo(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	kubernetesConfig := GetConfig(d.Connection)
+	enabled := slices.Contains(h.Item.(ConnectionInfo).SourceTypes, Helm.String())
+
+	var names []string
+	for name := range kubernetesConfig.HelmRenderedCharts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	charts := []ConnectionHelmChartInfo{}
+	for _, name := range names {
+		c := kubernetesConfig.HelmRenderedCharts[name]
+		chart := ConnectionHelmChartInfo{
+			Name:            name,
+			ChartPath:       c.ChartPath,
+			ValuesFilePaths: c.ValuesFilePaths,
+			Status:          "disabled",
+		}
+
+		// The charts are only rendered if the helm source is enabled
+		if enabled {
+			renders, err := getHelmChartRenders(ctx, d)
+			if err != nil {
+				return nil, err
+			}
+
+			render := renders[name]
+			chart.Templates = len(render.Documents)
+			if render.Err != nil {
+				chart.Status = "failed"
+				chart.Error = render.Err.Error()
+			} else {
+				chart.Status = "rendered"
+			}
+		}
+
+		charts = append(charts, chart)
+	}
+
+	return charts, nil
+}
+
+func getK8sConnectionCustomResourceInfo(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	info := ConnectionCustomResourceInfo{CustomResources: []ConnectionCustomResource{}}
+
+	crds, err := listK8sDynamicCRDs(ctx, d.ConnectionCache, d.Connection)
+	if err != nil {
+		info.Error = err.Error()
+		return info, nil
+	}
+
+	// The table names are resolved in the same order as the tables are created, to report the same name collisions
+	tables := getStaticTables(ctx)
+	for _, crd := range crds {
+		resource := ConnectionCustomResource{
+			Name:     crd.Name,
+			Group:    crd.Spec.Group,
+			Kind:     crd.Spec.Names.Kind,
+			Scope:    string(crd.Spec.Scope),
+			Versions: []string{},
+			Tables:   []string{},
+		}
+		for _, version := range crd.Spec.Versions {
+			if version.Served {
+				resource.Versions = append(resource.Versions, version.Name)
+			}
+		}
+
+		// No table is created for the CRDs without a served version
+		if getCustomResourceDefaultVersion(crd) != nil {
+			resource.Tables = getCustomResourceTableNames(crd, tables)
+		}
+		for _, tableName := range resource.Tables {
+			tables[tableName] = &plugin.Table{Name: tableName}
+		}
+
+		info.CustomResources = append(info.CustomResources, resource)
+	}
+
+	return info, nil
+}
+
+// getAuthMethod returns the method used to authenticate to the API server with the given config, i.e. exec, token, token_file,
+// client_certificate, basic, none, or the name of the auth provider, e.g. oidc
+func getAuthMethod(restconfig *rest.Config) string {
+	switch {
+	case restconfig.ExecProvider != nil:
+		return "exec"
+	case restconfig.AuthProvider != nil:
+		return restconfig.AuthProvider.Name
+	case restconfig.BearerToken != "":
+		return "token"
+	case restconfig.BearerTokenFile != "":
+		return "token_file"
+	case len(restconfig.TLSClientConfig.CertData) > 0 || restconfig.TLSClientConfig.CertFile != "":
+		return "client_certificate"
+	case restconfig.Username != "":
+		return "basic"
+	}
+	return "none"
+}
@@ -0,0 +1,29 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestGetAuthMethod(t *testing.T) {
+	tests := []struct {
+		name       string
+		restconfig *rest.Config
+		expected   string
+	}{
+		{name: "exec", restconfig: &rest.Config{ExecProvider: &clientcmdapi.ExecConfig{Command: "aws"}, BearerToken: "token"}, expected: "exec"},
+		{name: "auth provider", restconfig: &rest.Config{AuthProvider: &clientcmdapi.AuthProviderConfig{Name: "oidc"}}, expected: "oidc"},
+		{name: "token", restconfig: &rest.Config{BearerToken: "token"}, expected: "token"},
+		{name: "token file", restconfig: &rest.Config{BearerTokenFile: "/var/run/secrets/token"}, expected: "token_file"},
+		{name: "client certificate", restconfig: &rest.Config{TLSClientConfig: rest.TLSClientConfig{CertFile: "/tmp/client.crt", KeyFile: "/tmp/client.key"}}, expected: "client_certificate"},
+		{name: "basic", restconfig: &rest.Config{Username: "admin", Password: "secret"}, expected: "basic"},
+		{name: "none", restconfig: &rest.Config{}, expected: "none"},
+	}
+	for _, tt := range tests {
+		if got := getAuthMethod(tt.restconfig); got != tt.expected {
+			t.Errorf("%s: getAuthMethod() = %q, expected %q", tt.name, got, tt.expected)
+		}
+	}
+}
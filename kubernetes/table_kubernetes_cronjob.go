@@ -188,7 +188,7 @@ func listK8sCronJobs(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 	var response *v1.CronJobList
 	pageLeft := true
 	for pageLeft {
-		response, err = clientset.BatchV1().CronJobs(d.EqualsQualString("namespace")).List(ctx, input)
+		response, err = clientset.BatchV1().CronJobs(getListNamespace(ctx, d)).List(ctx, input)
 		if err != nil {
 			logger.Error("listK8sCronJobs", "list_err", err)
 			return nil, err
@@ -201,6 +201,11 @@ func listK8sCronJobs(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 		}
 
 		for _, cronJob := range response.Items {
+			// Skip the resources of the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, cronJob.Namespace) {
+				continue
+			}
+
 			d.StreamListItem(ctx, CronJob{cronJob, parsedContent{SourceType: "deployed"}})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -232,6 +237,11 @@ func getK8sCronJob(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDa
 		return nil, nil
 	}
 
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, namespace) {
+		return nil, nil
+	}
+
 	// Get the manifest resource
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "CronJob")
 	if err != nil {
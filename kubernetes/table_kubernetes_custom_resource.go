@@ -2,29 +2,38 @@ package kubernetes
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/iancoleman/strcase"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/util/jsonpath"
 )
 
 func tableKubernetesCustomResource(ctx context.Context) *plugin.Table {
 	crdName := ctx.Value(contextKey("CRDName")).(string)
 	resourceName := ctx.Value(contextKey("CustomResourceName")).(string)
-	resourceNameSingular := ctx.Value(contextKey("CustomResourceNameSingular")).(string)
+	kind := ctx.Value(contextKey("CustomResourceKind")).(string)
 	groupName := ctx.Value(contextKey("GroupName")).(string)
+	scope, _ := ctx.Value(contextKey("Scope")).(string)
 	activeVersion := ctx.Value(contextKey("ActiveVersion")).(string)
+	servedVersions, _ := ctx.Value(contextKey("ServedVersions")).([]string)
 	versionSchemaSpec := ctx.Value(contextKey("VersionSchemaSpec"))
 	versionSchemaStatus := ctx.Value(contextKey("VersionSchemaStatus"))
+	printerColumns, _ := ctx.Value(contextKey("AdditionalPrinterColumns")).([]v1.CustomResourceColumnDefinition)
+	columnDepth, _ := ctx.Value(contextKey("ColumnDepth")).(int)
 	tableName := ctx.Value(contextKey("TableName")).(string)
 
 	var description string
@@ -33,21 +42,40 @@ func tableKubernetesCustomResource(ctx context.Context) *plugin.Table {
 	} else {
 		description = ctx.Value(contextKey("VersionSchemaDescription")).(string) + " Custom resource for " + crdName + "."
 	}
+
+	// Cluster scoped resources are identified by their name only
+	getKeyColumns := plugin.AllColumns([]string{"name", "namespace"})
+	if scope == string(v1.ClusterScoped) {
+		getKeyColumns = plugin.SingleColumn("name")
+	}
+
 	return &plugin.Table{
 		Name:        tableName,
 		Description: description,
+		Get: &plugin.GetConfig{
+			KeyColumns: getKeyColumns,
+			Hydrate:    getK8sCustomResource(ctx, resourceName, kind, groupName, activeVersion, servedVersions, scope),
+		},
 		List: &plugin.ListConfig{
-			Hydrate: listK8sCustomResources(ctx, crdName, resourceName, resourceNameSingular, groupName, activeVersion),
+			Hydrate: listK8sCustomResources(ctx, crdName, resourceName, kind, groupName, activeVersion, servedVersions, scope),
+			KeyColumns: getOptionalKeyQualWithCommonKeyQuals([]*plugin.KeyColumn{
+				{Name: "label_selector", Require: plugin.Optional, CacheMatch: "exact"},
+				{Name: "field_selector", Require: plugin.Optional, CacheMatch: "exact"},
+			}),
 		},
-		Columns: k8sCRDResourceCommonColumns(getCustomResourcesDynamicColumns(ctx, versionSchemaSpec, versionSchemaStatus)),
+		Columns: k8sCRDResourceCommonColumns(append([]*plugin.Column{
+			{Name: "version", Type: proto.ColumnType_STRING, Description: "The API version of the resource, without the group, e.g. v1. The deployed resources are listed using the storage version of the CRD, while the manifest resources keep the version they are defined with."},
+			{Name: "label_selector", Type: proto.ColumnType_STRING, Description: "A label selector string to restrict the list of returned objects by their labels.", Transform: transform.FromQual("label_selector")},
+			{Name: "field_selector", Type: proto.ColumnType_STRING, Description: "A field selector string to restrict the list of returned objects by their fields. Custom resources support the metadata.name and metadata.namespace fields, along with the selectableFields declared in the CRD.", Transform: transform.FromQual("field_selector")},
+		}, getCustomResourcesDynamicColumns(ctx, versionSchemaSpec, versionSchemaStatus, printerColumns, columnDepth)...)),
 	}
 }
 
-func getCustomResourcesDynamicColumns(ctx context.Context, versionSchemaSpec interface{}, versionSchemaStatus interface{}) []*plugin.Column {
+func getCustomResourcesDynamicColumns(ctx context.Context, versionSchemaSpec interface{}, versionSchemaStatus interface{}, printerColumns []v1.CustomResourceColumnDefinition, columnDepth int) []*plugin.Column {
 	columns := []*plugin.Column{}
 
 	// default metadata columns
-	allColumns := []string{"name", "uid", "kind", "api_version", "namespace", "creation_timestamp", "labels", "start_line", "end_line", "path", "source_type", "annotations", "context_name"}
+	allColumns := []string{"name", "uid", "kind", "api_version", "namespace", "creation_timestamp", "labels", "start_line", "end_line", "path", "source_type", "annotations", "context_name", "label_selector", "field_selector", "version"}
 
 	// add the spec columns
 	schemaSpec := versionSchemaSpec.(v1.JSONSchemaProps)
@@ -104,15 +132,95 @@ func getCustomResourcesDynamicColumns(ctx context.Context, versionSchemaSpec int
 		}
 	}
 
+	// add the columns for the nested spec and status properties, e.g. spec_issuer_ref_name
+	columnNames := map[string]bool{}
+	for _, name := range allColumns {
+		columnNames[name] = true
+	}
+	for _, column := range columns {
+		columnNames[column.Name] = true
+	}
+
+	var nestedColumns []*plugin.Column
+	for _, k := range sortedSchemaPropertyNames(schemaSpec) {
+		nestedColumns = append(nestedColumns, getCustomResourceNestedColumns("spec_"+strcase.ToSnake(k), []string{"spec", k}, schemaSpec.Properties[k], columnDepth)...)
+	}
+	for _, k := range sortedSchemaPropertyNames(schemaStatus) {
+		nestedColumns = append(nestedColumns, getCustomResourceNestedColumns("status_"+strcase.ToSnake(k), []string{"status", k}, schemaStatus.Properties[k], columnDepth)...)
+	}
+	for _, column := range nestedColumns {
+		if !columnNames[column.Name] {
+			columnNames[column.Name] = true
+			columns = append(columns, column)
+		}
+	}
+
+	// add the additional printer columns, as displayed by kubectl get
+	for _, printerColumn := range printerColumns {
+		name := strcase.ToSnake(printerColumn.Name)
+		if columnNames[name] {
+			name = "printer_" + name
+		}
+		if columnNames[name] {
+			continue
+		}
+		columnNames[name] = true
+
+		column := &plugin.Column{
+			Name:        name,
+			Description: printerColumn.Description,
+			Transform:   transform.FromP(extractPrinterColumnValue, printerColumn.JSONPath),
+		}
+		setPrinterColumnType(printerColumn, column)
+		columns = append(columns, column)
+	}
+
+	return columns
+}
+
+// getCustomResourceNestedColumns returns the columns for the nested properties of the given object property, up to the given depth.
+// The columns are named after the full path of the property, e.g. spec_issuer_ref_name for spec.issuerRef.name.
+func getCustomResourceNestedColumns(prefix string, path []string, v v1.JSONSchemaProps, depth int) []*plugin.Column {
+	if depth <= 1 {
+		return nil
+	}
+
+	var columns []*plugin.Column
+	for _, k := range sortedSchemaPropertyNames(v) {
+		property := v.Properties[k]
+		columnName := prefix + "_" + strcase.ToSnake(k)
+		propertyPath := append(slices.Clone(path), k)
+
+		column := &plugin.Column{
+			Name:        columnName,
+			Description: property.Description,
+			Transform:   transform.FromP(extractCustomResourceProperty, propertyPath),
+		}
+		setDynamicColumns(property, column)
+		columns = append(columns, column)
+		columns = append(columns, getCustomResourceNestedColumns(columnName, propertyPath, property, depth-1)...)
+	}
+
 	return columns
 }
 
+// sortedSchemaPropertyNames returns the names of the properties of the given schema in a consistent order
+func sortedSchemaPropertyNames(v v1.JSONSchemaProps) []string {
+	var names []string
+	for k := range v.Properties {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
 type CRDResourceInfo struct {
 	Name              interface{}
 	UID               interface{}
 	CreationTimestamp interface{}
 	Kind              interface{}
 	APIVersion        interface{}
+	Version           string
 	Namespace         interface{}
 	Annotations       interface{}
 	Spec              interface{}
@@ -123,25 +231,48 @@ type CRDResourceInfo struct {
 	EndLine           int
 	SourceType        string
 	ContextName       string
+	Object            map[string]interface{}
 }
 
 // //// HYDRATE FUNCTIONS
 
-func listK8sCustomResources(ctx context.Context, crdName string, resourceName string, resourceNameSingular string, groupName string, activeVersion string) func(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+func listK8sCustomResources(ctx context.Context, crdName string, resourceName string, kind string, groupName string, activeVersion string, servedVersions []string, scope string) func(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
 	return func(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
 		clientset, err := GetNewClientDynamic(ctx, d)
 		if err != nil {
 			return nil, err
 		}
 
+		namespace := d.EqualsQualString("namespace")
+
+		// Cluster scoped resources do not belong to any namespace
+		if scope == string(v1.ClusterScoped) && namespace != "" {
+			return nil, nil
+		}
+
+		labelSelector, err := labels.Parse(d.EqualsQualString("label_selector"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid label_selector: %v", err)
+		}
+
+		fieldSelectorValues := getCommonOptionalKeyQualsValueForFieldSelector(d)
+		if namespace != "" {
+			fieldSelectorValues = append(fieldSelectorValues, fmt.Sprintf("metadata.namespace=%v", namespace))
+		}
+		if d.EqualsQualString("field_selector") != "" {
+			fieldSelectorValues = append(fieldSelectorValues, d.EqualsQualString("field_selector"))
+		}
+		fieldSelector, err := fields.ParseSelector(strings.Join(fieldSelectorValues, ","))
+		if err != nil {
+			return nil, fmt.Errorf("invalid field_selector: %v", err)
+		}
+
 		//
 		// Check for manifest files
 		//
 
-		// In general, the kind of the custom resource must be same as the singular name defined in the CRD
-		// Convert the singular name into title format, e.g. if the name is `certificate`, the custom resource kind must be `Certificate`
-		caser := cases.Title(language.English)
-		parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, caser.String(resourceNameSingular))
+		// The kind of the custom resource must be the kind defined in the CRD, e.g. `ClusterIssuer`
+		parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, kind)
 		if err != nil {
 			return nil, err
 		}
@@ -149,28 +280,22 @@ func listK8sCustomResources(ctx context.Context, crdName string, resourceName st
 		for _, content := range parsedContents {
 			deployment := content.ParsedData.(*unstructured.Unstructured)
 
-			// Also, the apiVersion of the custom resource must be in format of <groupName in CRD>/<spec version in CRD>
-			if !(deployment.GetAPIVersion() == fmt.Sprintf("%s/%s", groupName, activeVersion)) {
+			// Also, the apiVersion of the custom resource must be in format of <groupName in CRD>/<served version in CRD>
+			if !isServedCustomResourceVersion(deployment, groupName, servedVersions) {
 				continue
 			}
 
-			data := deployment.Object
-			d.StreamListItem(ctx, &CRDResourceInfo{
-				Name:              deployment.GetName(),
-				UID:               deployment.GetUID(),
-				APIVersion:        deployment.GetAPIVersion(),
-				Kind:              deployment.GetKind(),
-				Namespace:         deployment.GetNamespace(),
-				CreationTimestamp: deployment.GetCreationTimestamp(),
-				Annotations:       deployment.GetAnnotations(),
-				Labels:            deployment.GetLabels(),
-				Spec:              data["spec"],
-				Status:            data["status"],
-				StartLine:         content.StartLine,
-				EndLine:           content.EndLine,
-				Path:              content.Path,
-				SourceType:        content.SourceType,
-			})
+			// The selectors are applied on the manifest resources in the same way the API server does, for the metadata fields
+			if !matchesCustomResourceSelectors(deployment, labelSelector, fieldSelector) {
+				continue
+			}
+
+			d.StreamListItem(ctx, newCRDResourceInfo(deployment, content))
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
 		}
 
 		//
@@ -186,43 +311,188 @@ func listK8sCustomResources(ctx context.Context, crdName string, resourceName st
 			Resource: resourceName,
 		}
 
-		response, err := clientset.Resource(resourceId).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			// Handle not found error code
-			if strings.Contains(err.Error(), "could not find the requested resource") {
-				return nil, nil
+		input := metav1.ListOptions{
+			Limit:         500,
+			LabelSelector: d.EqualsQualString("label_selector"),
+			FieldSelector: strings.Join(fieldSelectorValues, ","),
+		}
+
+		// Limiting the results
+		limit := d.QueryContext.Limit
+		if d.QueryContext.Limit != nil {
+			if *limit < input.Limit {
+				if *limit < 1 {
+					input.Limit = 1
+				} else {
+					input.Limit = *limit
+				}
+			}
+		}
+
+		currentContext, _ := getCurrentContext(ctx, d, nil).(string)
+
+		// The namespaced resources are listed from the single namespace included in the connection, if any
+		listNamespace := namespace
+		if scope != string(v1.ClusterScoped) {
+			listNamespace = getListNamespace(ctx, d)
+		}
+
+		pageLeft := true
+		for pageLeft {
+			response, err := clientset.Resource(resourceId).Namespace(listNamespace).List(ctx, input)
+			if err != nil {
+				// Handle not found error code
+				if strings.Contains(err.Error(), "could not find the requested resource") {
+					return nil, nil
+				}
+				return nil, err
+			}
+
+			if response.GetContinue() != "" {
+				input.Continue = response.GetContinue()
+			} else {
+				pageLeft = false
 			}
+
+			for _, crd := range response.Items {
+				// Skip the resources of the namespaces which are not included in the connection
+				if !isNamespaceIncluded(d, crd.GetNamespace()) {
+					continue
+				}
+
+				item := newCRDResourceInfo(&crd, parsedContent{SourceType: "deployed"})
+				item.ContextName = currentContext
+				d.StreamListItem(ctx, item)
+
+				// Context can be cancelled due to manual cancellation or the limit has been hit
+				if d.RowsRemaining(ctx) == 0 {
+					return nil, nil
+				}
+			}
+		}
+
+		return nil, nil
+	}
+
+}
+
+func getK8sCustomResource(ctx context.Context, resourceName string, kind string, groupName string, activeVersion string, servedVersions []string, scope string) func(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	return func(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+		clientset, err := GetNewClientDynamic(ctx, d)
+		if err != nil {
 			return nil, err
 		}
 
-		currentContext := getCurrentContext(ctx, d, nil)
-
-		for _, crd := range response.Items {
-			data := crd.Object
-			d.StreamListItem(ctx, &CRDResourceInfo{
-				Name:              crd.GetName(),
-				UID:               crd.GetUID(),
-				APIVersion:        crd.GetAPIVersion(),
-				Kind:              crd.GetKind(),
-				Namespace:         crd.GetNamespace(),
-				Annotations:       crd.GetAnnotations(),
-				CreationTimestamp: crd.GetCreationTimestamp(),
-				Labels:            crd.GetLabels(),
-				Spec:              data["spec"],
-				Status:            data["status"],
-				SourceType:        "deployed",
-				ContextName:       currentContext.(string),
-			})
+		name := d.EqualsQualString("name")
+		namespace := d.EqualsQualString("namespace")
 
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if d.RowsRemaining(ctx) == 0 {
+		// return if namespace or name is empty
+		if name == "" || (scope != string(v1.ClusterScoped) && namespace == "") {
+			return nil, nil
+		}
+
+		// return if the namespace is not included in the connection
+		if !isNamespaceIncluded(d, namespace) {
+			return nil, nil
+		}
+
+		// Get the manifest resource
+		parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, kind)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, content := range parsedContents {
+			resource := content.ParsedData.(*unstructured.Unstructured)
+
+			if isServedCustomResourceVersion(resource, groupName, servedVersions) && resource.GetName() == name && resource.GetNamespace() == namespace {
+				return newCRDResourceInfo(resource, content), nil
+			}
+		}
+
+		// Get the deployed resource
+		if clientset == nil {
+			return nil, nil
+		}
+
+		resourceId := schema.GroupVersionResource{
+			Group:    groupName,
+			Version:  activeVersion,
+			Resource: resourceName,
+		}
+
+		resource, err := clientset.Resource(resourceId).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if isNotFoundError(err) || strings.Contains(err.Error(), "could not find the requested resource") {
 				return nil, nil
 			}
+			return nil, err
 		}
 
-		return nil, nil
+		item := newCRDResourceInfo(resource, parsedContent{SourceType: "deployed"})
+		item.ContextName, _ = getCurrentContext(ctx, d, nil).(string)
+
+		return item, nil
+	}
+}
+
+// isServedCustomResourceVersion checks whether the apiVersion of the given custom resource is one of the served versions of the CRD
+func isServedCustomResourceVersion(resource *unstructured.Unstructured, groupName string, servedVersions []string) bool {
+	gvk := resource.GroupVersionKind()
+	return gvk.Group == groupName && slices.Contains(servedVersions, gvk.Version)
+}
+
+// newCRDResourceInfo returns the row of the given custom resource, read from either the cluster or the given manifest content
+func newCRDResourceInfo(resource *unstructured.Unstructured, content parsedContent) *CRDResourceInfo {
+	data := resource.Object
+	return &CRDResourceInfo{
+		Name:              resource.GetName(),
+		UID:               resource.GetUID(),
+		APIVersion:        resource.GetAPIVersion(),
+		Version:           resource.GroupVersionKind().Version,
+		Kind:              resource.GetKind(),
+		Namespace:         resource.GetNamespace(),
+		CreationTimestamp: resource.GetCreationTimestamp(),
+		Annotations:       resource.GetAnnotations(),
+		Labels:            resource.GetLabels(),
+		Spec:              data["spec"],
+		Status:            data["status"],
+		Object:            data,
+		StartLine:         content.StartLine,
+		EndLine:           content.EndLine,
+		Path:              content.Path,
+		SourceType:        content.SourceType,
+	}
+}
+
+// matchesCustomResourceSelectors checks whether the given custom resource matches the label and field selectors.
+// Only the metadata fields are available for the field selector, since the selectable fields of the CRD are evaluated by the API server.
+func matchesCustomResourceSelectors(resource *unstructured.Unstructured, labelSelector labels.Selector, fieldSelector fields.Selector) bool {
+	if !labelSelector.Matches(labels.Set(resource.GetLabels())) {
+		return false
 	}
 
+	fieldSet := fields.Set{
+		"metadata.name":      resource.GetName(),
+		"metadata.namespace": resource.GetNamespace(),
+	}
+	for _, requirement := range fieldSelector.Requirements() {
+		value, ok := fieldSet[requirement.Field]
+		if !ok {
+			// Unknown fields can't be evaluated on the manifest resources, so they are not used to filter out the resource
+			continue
+		}
+
+		matches := value == requirement.Value
+		if requirement.Operator == selection.NotEquals {
+			matches = !matches
+		}
+		if !matches {
+			return false
+		}
+	}
+
+	return true
 }
 
 func extractSpecProperty(_ context.Context, d *transform.TransformData) (interface{}, error) {
@@ -253,23 +523,121 @@ func extractStatusProperty(_ context.Context, d *transform.TransformData) (inter
 	return nil, nil
 }
 
+// extractCustomResourceProperty returns the value of the property at the given path in the custom resource
+func extractCustomResourceProperty(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	value, found, err := unstructured.NestedFieldNoCopy(d.HydrateItem.(*CRDResourceInfo).Object, d.Param.([]string)...)
+	if err != nil || !found {
+		return nil, nil
+	}
+
+	return value, nil
+}
+
+// extractPrinterColumnValue evaluates the JSONPath of an additional printer column against the custom resource
+func extractPrinterColumnValue(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	return evaluateJSONPath(d.HydrateItem.(*CRDResourceInfo).Object, d.Param.(string))
+}
+
+// evaluateJSONPath returns the value at the given JSONPath, e.g. .status.conditions[?(@.type=="Ready")].status.
+// If the path matches multiple values, they are joined with commas like kubectl does, e.g. Ready,Issuing,
+// the maps and lists being JSON encoded. Returns nil if nothing matches.
+func evaluateJSONPath(object map[string]interface{}, path string) (interface{}, error) {
+	if object == nil {
+		return nil, nil
+	}
+
+	parser := jsonpath.New("printerColumn").AllowMissingKeys(true)
+	if err := parser.Parse(fmt.Sprintf("{%s}", path)); err != nil {
+		return nil, fmt.Errorf("invalid JSONPath %s: %v", path, err)
+	}
+
+	results, err := parser.FindResults(object)
+	if err != nil {
+		return nil, nil
+	}
+
+	var values []interface{}
+	for _, result := range results {
+		for _, value := range result {
+			if value.IsValid() && value.CanInterface() {
+				values = append(values, value.Interface())
+			}
+		}
+	}
+
+	switch len(values) {
+	case 0:
+		return nil, nil
+	case 1:
+		return values[0], nil
+	}
+
+	joined := make([]string, 0, len(values))
+	for _, value := range values {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			data, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			joined = append(joined, string(data))
+		default:
+			joined = append(joined, fmt.Sprint(value))
+		}
+	}
+	return strings.Join(joined, ","), nil
+}
+
 func setDynamicColumns(v v1.JSONSchemaProps, column *plugin.Column) {
+	// int-or-string properties, e.g. ports and percentages, are exposed as strings
+	if v.XIntOrString {
+		column.Type = proto.ColumnType_STRING
+		return
+	}
+
+	// Objects with unknown fields can't be typed
+	if v.XPreserveUnknownFields != nil && *v.XPreserveUnknownFields {
+		column.Type = proto.ColumnType_JSON
+		return
+	}
+
 	switch v.Type {
 	case "string":
-		column.Type = proto.ColumnType_STRING
+		if v.Format == "date-time" || v.Format == "date" {
+			column.Type = proto.ColumnType_TIMESTAMP
+		} else {
+			column.Type = proto.ColumnType_STRING
+		}
 	case "integer":
 		column.Type = proto.ColumnType_INT
 	case "boolean":
 		column.Type = proto.ColumnType_BOOL
 	case "date", "dateTime":
 		column.Type = proto.ColumnType_TIMESTAMP
-	case "double":
+	case "number", "double":
 		column.Type = proto.ColumnType_DOUBLE
 	default:
 		column.Type = proto.ColumnType_JSON
 	}
 }
 
+// setPrinterColumnType sets the column type based on the type of the additional printer column.
+// See https://kubernetes.io/docs/tasks/extend-kubernetes/custom-resources/custom-resource-definitions/#type
+func setPrinterColumnType(printerColumn v1.CustomResourceColumnDefinition, column *plugin.Column) {
+	switch printerColumn.Type {
+	case "integer":
+		column.Type = proto.ColumnType_INT
+	case "number":
+		column.Type = proto.ColumnType_DOUBLE
+	case "boolean":
+		column.Type = proto.ColumnType_BOOL
+	case "date":
+		column.Type = proto.ColumnType_TIMESTAMP
+	default:
+		column.Type = proto.ColumnType_STRING
+	}
+}
+
 func getCurrentContext(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) any {
 	currentContext, err := getKubectlContext(ctx, d, nil)
 	if err != nil {
@@ -0,0 +1,189 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func tableKubernetesCustomResourceDefinitionProperty(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "kubernetes_custom_resource_definition_property",
+		Description: "Kubernetes Custom Resource Definition Property, one row per property of the schema of every served version.",
+		List: &plugin.ListConfig{
+			ParentHydrate: listK8sCustomResourceDefinitions,
+			Hydrate:       listK8sCustomResourceDefinitionProperties,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "crd_name", Require: plugin.Optional},
+				{Name: "version", Require: plugin.Optional},
+			},
+		},
+		Columns: append([]*plugin.Column{
+			{Name: "crd_name", Type: proto.ColumnType_STRING, Description: "The name of the custom resource definition.", Transform: transform.FromField("CRDName")},
+			{Name: "group", Type: proto.ColumnType_STRING, Description: "The API group of the custom resource."},
+			{Name: "kind", Type: proto.ColumnType_STRING, Description: "The kind of the custom resource."},
+			{Name: "version", Type: proto.ColumnType_STRING, Description: "The name of the version of the custom resource, e.g. v1."},
+			{Name: "storage", Type: proto.ColumnType_BOOL, Description: "True if the version is used when persisting the custom resources to storage."},
+			{Name: "deprecated", Type: proto.ColumnType_BOOL, Description: "True if the version is deprecated."},
+			{Name: "json_path", Type: proto.ColumnType_STRING, Description: "The JSON path of the property in the custom resource, e.g. .spec.issuerRef.name. The items of arrays are denoted by [*], and the values of maps by .*.", Transform: transform.FromField("JSONPath")},
+			{Name: "parent_json_path", Type: proto.ColumnType_STRING, Description: "The JSON path of the parent property. Empty for the top level properties.", Transform: transform.FromField("ParentJSONPath").Transform(transform.NullIfZeroValue)},
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "The name of the property."},
+			{Name: "depth", Type: proto.ColumnType_INT, Description: "The depth of the property in the schema, starting at 1 for the top level properties."},
+			{Name: "type", Type: proto.ColumnType_STRING, Description: "The type of the property, e.g. string, integer, object or array.", Transform: transform.FromField("Schema.Type").Transform(transform.NullIfZeroValue)},
+			{Name: "items_type", Type: proto.ColumnType_STRING, Description: "The type of the items of an array property.", Transform: transform.FromField("Schema.Items.Schema.Type").Transform(transform.NullIfZeroValue)},
+			{Name: "format", Type: proto.ColumnType_STRING, Description: "The format of the property, e.g. date-time or int32.", Transform: transform.FromField("Schema.Format").Transform(transform.NullIfZeroValue)},
+			{Name: "required", Type: proto.ColumnType_BOOL, Description: "True if the property is required by its parent property."},
+			{Name: "nullable", Type: proto.ColumnType_BOOL, Description: "True if the property can be null.", Transform: transform.FromField("Schema.Nullable")},
+			{Name: "default", Type: proto.ColumnType_JSON, Description: "The default value of the property."},
+			{Name: "enum", Type: proto.ColumnType_JSON, Description: "The allowed values of the property."},
+			{Name: "pattern", Type: proto.ColumnType_STRING, Description: "The regular expression the string values of the property must match.", Transform: transform.FromField("Schema.Pattern").Transform(transform.NullIfZeroValue)},
+			{Name: "minimum", Type: proto.ColumnType_DOUBLE, Description: "The minimum value of a numeric property.", Transform: transform.FromField("Schema.Minimum")},
+			{Name: "maximum", Type: proto.ColumnType_DOUBLE, Description: "The maximum value of a numeric property.", Transform: transform.FromField("Schema.Maximum")},
+			{Name: "min_length", Type: proto.ColumnType_INT, Description: "The minimum length of a string property.", Transform: transform.FromField("Schema.MinLength")},
+			{Name: "max_length", Type: proto.ColumnType_INT, Description: "The maximum length of a string property.", Transform: transform.FromField("Schema.MaxLength")},
+			{Name: "min_items", Type: proto.ColumnType_INT, Description: "The minimum number of items of an array property.", Transform: transform.FromField("Schema.MinItems")},
+			{Name: "max_items", Type: proto.ColumnType_INT, Description: "The maximum number of items of an array property.", Transform: transform.FromField("Schema.MaxItems")},
+			{Name: "description", Type: proto.ColumnType_STRING, Description: "The description of the property.", Transform: transform.FromField("Schema.Description").Transform(transform.NullIfZeroValue)},
+			{Name: "x_kubernetes_preserve_unknown_fields", Type: proto.ColumnType_BOOL, Description: "True if the fields which are not specified in the schema are preserved, instead of being pruned.", Transform: transform.FromField("Schema.XPreserveUnknownFields")},
+			{Name: "x_kubernetes_int_or_string", Type: proto.ColumnType_BOOL, Description: "True if the property can be either an integer or a string.", Transform: transform.FromField("Schema.XIntOrString")},
+			{Name: "x_kubernetes_embedded_resource", Type: proto.ColumnType_BOOL, Description: "True if the property is an embedded Kubernetes object, with apiVersion, kind and metadata.", Transform: transform.FromField("Schema.XEmbeddedResource")},
+			{Name: "x_kubernetes_list_type", Type: proto.ColumnType_STRING, Description: "The list type of an array property. Possible values are: atomic, set and map.", Transform: transform.FromField("Schema.XListType")},
+			{Name: "x_kubernetes_list_map_keys", Type: proto.ColumnType_JSON, Description: "The keys identifying the items of an array property with the map list type.", Transform: transform.FromField("Schema.XListMapKeys")},
+			{Name: "x_kubernetes_map_type", Type: proto.ColumnType_STRING, Description: "The map type of an object property. Possible values are: granular and atomic.", Transform: transform.FromField("Schema.XMapType")},
+			{Name: "x_kubernetes_validations", Type: proto.ColumnType_JSON, Description: "The CEL validation rules of the property.", Transform: transform.FromField("Schema.XValidations")},
+			{Name: "context_name", Type: proto.ColumnType_STRING, Description: "Kubectl config context name.", Transform: transform.FromField("ContextName").Transform(transform.NullIfZeroValue)},
+			{Name: "source_type", Type: proto.ColumnType_STRING, Description: "The source of the custom resource definition. Possible values are: deployed and manifest. If the resource is fetched from the spec file the value will be manifest."},
+		}, manifestResourceColumns()...),
+	}
+}
+
+type CustomResourceDefinitionProperty struct {
+	CRDName        string
+	Group          string
+	Kind           string
+	Version        string
+	Storage        bool
+	Deprecated     bool
+	JSONPath       string
+	ParentJSONPath string
+	Name           string
+	Depth          int
+	Required       bool
+	Default        interface{}
+	Enum           []interface{}
+	Schema         v1.JSONSchemaProps
+	ContextName    string
+	Path           string
+	StartLine      int
+	EndLine        int
+	SourceType     string
+}
+
+//// HYDRATE FUNCTIONS
+
+func listK8sCustomResourceDefinitionProperties(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	crd := h.Item.(CustomResourceDefinition)
+
+	if d.EqualsQualString("crd_name") != "" && d.EqualsQualString("crd_name") != crd.Name {
+		return nil, nil
+	}
+
+	var contextName string
+	if crd.SourceType == "deployed" {
+		contextName, _ = getCurrentContext(ctx, d, nil).(string)
+	}
+
+	for _, version := range crd.Spec.Versions {
+		if !version.Served {
+			continue
+		}
+		if d.EqualsQualString("version") != "" && d.EqualsQualString("version") != version.Name {
+			continue
+		}
+		if version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+
+		for _, property := range getCustomResourceDefinitionProperties("", *version.Schema.OpenAPIV3Schema, 1) {
+			property.CRDName = crd.Name
+			property.Group = crd.Spec.Group
+			property.Kind = crd.Spec.Names.Kind
+			property.Version = version.Name
+			property.Storage = version.Storage
+			property.Deprecated = version.Deprecated
+			property.ContextName = contextName
+			property.Path = crd.Path
+			property.StartLine = crd.StartLine
+			property.EndLine = crd.EndLine
+			property.SourceType = crd.SourceType
+			d.StreamListItem(ctx, property)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// getCustomResourceDefinitionProperties walks the given schema and returns one item per property, parents first.
+// The properties of the array items are prefixed by [*], and the properties of the map values by .*
+func getCustomResourceDefinitionProperties(parentPath string, schema v1.JSONSchemaProps, depth int) []CustomResourceDefinitionProperty {
+	var properties []CustomResourceDefinitionProperty
+	for _, name := range sortedSchemaPropertyNames(schema) {
+		property := schema.Properties[name]
+		jsonPath := parentPath + "." + name
+
+		item := CustomResourceDefinitionProperty{
+			JSONPath:       jsonPath,
+			ParentJSONPath: parentPath,
+			Name:           name,
+			Depth:          depth,
+			Required:       slices.Contains(schema.Required, name),
+			Default:        decodeSchemaJSON(property.Default),
+			Schema:         property,
+		}
+		for _, value := range property.Enum {
+			item.Enum = append(item.Enum, decodeSchemaJSON(&value))
+		}
+
+		// The nested properties are returned separately, hence dropped from the schema of the item
+		item.Schema.Properties = nil
+		if property.Items != nil && property.Items.Schema != nil {
+			items := *property.Items.Schema
+			items.Properties = nil
+			item.Schema.Items = &v1.JSONSchemaPropsOrArray{Schema: &items}
+		}
+		properties = append(properties, item)
+
+		properties = append(properties, getCustomResourceDefinitionProperties(jsonPath, property, depth+1)...)
+		if property.Items != nil && property.Items.Schema != nil {
+			properties = append(properties, getCustomResourceDefinitionProperties(jsonPath+"[*]", *property.Items.Schema, depth+1)...)
+		}
+		if property.AdditionalProperties != nil && property.AdditionalProperties.Schema != nil {
+			properties = append(properties, getCustomResourceDefinitionProperties(jsonPath+".*", *property.AdditionalProperties.Schema, depth+1)...)
+		}
+	}
+
+	return properties
+}
+
+// decodeSchemaJSON decodes the raw JSON value of a schema field, e.g. the default value
+func decodeSchemaJSON(value *v1.JSON) interface{} {
+	if value == nil || len(value.Raw) == 0 {
+		return nil
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(value.Raw, &decoded); err != nil {
+		return string(value.Raw)
+	}
+	return decoded
+}
@@ -0,0 +1,90 @@
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestGetCustomResourceDefinitionProperties(t *testing.T) {
+	schema := v1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]v1.JSONSchemaProps{
+			"spec": {
+				Type:     "object",
+				Required: []string{"issuerRef"},
+				Properties: map[string]v1.JSONSchemaProps{
+					"issuerRef": {
+						Type: "object",
+						Properties: map[string]v1.JSONSchemaProps{
+							"name": {Type: "string"},
+						},
+					},
+					"usages": {
+						Type: "array",
+						Items: &v1.JSONSchemaPropsOrArray{Schema: &v1.JSONSchemaProps{
+							Type: "string",
+							Enum: []v1.JSON{{Raw: []byte(`"server auth"`)}, {Raw: []byte(`"client auth"`)}},
+						}},
+					},
+					"duration": {Type: "string", Default: &v1.JSON{Raw: []byte(`"2160h"`)}},
+					"labels": {
+						Type:                 "object",
+						AdditionalProperties: &v1.JSONSchemaPropsOrBool{Schema: &v1.JSONSchemaProps{Type: "string"}},
+					},
+					"containers": {
+						Type: "array",
+						Items: &v1.JSONSchemaPropsOrArray{Schema: &v1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]v1.JSONSchemaProps{
+								"image": {Type: "string"},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	properties := getCustomResourceDefinitionProperties("", schema, 1)
+
+	var paths []string
+	for _, property := range properties {
+		paths = append(paths, property.JSONPath)
+	}
+	expected := []string{
+		".spec",
+		".spec.containers",
+		".spec.containers[*].image",
+		".spec.duration",
+		".spec.issuerRef",
+		".spec.issuerRef.name",
+		".spec.labels",
+		".spec.usages",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("getCustomResourceDefinitionProperties() paths = %v, expected %v", paths, expected)
+	}
+
+	byPath := map[string]CustomResourceDefinitionProperty{}
+	for _, property := range properties {
+		byPath[property.JSONPath] = property
+	}
+
+	if image := byPath[".spec.containers[*].image"]; image.ParentJSONPath != ".spec.containers[*]" || image.Depth != 3 {
+		t.Errorf("unexpected parent or depth for .spec.containers[*].image: %q, %d", image.ParentJSONPath, image.Depth)
+	}
+	if !byPath[".spec.issuerRef"].Required || byPath[".spec.duration"].Required {
+		t.Errorf("expected only .spec.issuerRef to be required")
+	}
+	if got := byPath[".spec.duration"].Default; got != "2160h" {
+		t.Errorf("expected default 2160h for .spec.duration, got %v", got)
+	}
+	if got := byPath[".spec.usages"].Schema.Items.Schema.Type; got != "string" {
+		t.Errorf("expected items type string for .spec.usages, got %q", got)
+	}
+	if byPath[".spec"].Schema.Properties != nil {
+		t.Errorf("expected the nested properties to be dropped from the schema of .spec")
+	}
+}
@@ -0,0 +1,143 @@
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestMatchesCustomResourceSelectors(t *testing.T) {
+	resource := &unstructured.Unstructured{}
+	resource.SetName("web-cert")
+	resource.SetNamespace("prod")
+	resource.SetLabels(map[string]string{"app": "web", "tier": "frontend"})
+
+	tests := []struct {
+		labelSelector string
+		fieldSelector string
+		expected      bool
+	}{
+		{"", "", true},
+		{"app=web", "", true},
+		{"app=api", "", false},
+		{"app in (web,api),tier!=backend", "", true},
+		{"!app", "", false},
+		{"", "metadata.name=web-cert", true},
+		{"", "metadata.name!=web-cert", false},
+		{"", "metadata.namespace=dev", false},
+		{"app=web", "metadata.namespace=prod,metadata.name=web-cert", true},
+		// Fields other than the metadata fields are evaluated by the API server only
+		{"", "spec.issuerRef.name=letsencrypt", true},
+	}
+
+	for _, tt := range tests {
+		labelSelector, err := labels.Parse(tt.labelSelector)
+		if err != nil {
+			t.Fatalf("labels.Parse(%q) failed: %v", tt.labelSelector, err)
+		}
+		fieldSelector, err := fields.ParseSelector(tt.fieldSelector)
+		if err != nil {
+			t.Fatalf("fields.ParseSelector(%q) failed: %v", tt.fieldSelector, err)
+		}
+
+		if got := matchesCustomResourceSelectors(resource, labelSelector, fieldSelector); got != tt.expected {
+			t.Errorf("matchesCustomResourceSelectors(%q, %q) = %v, expected %v", tt.labelSelector, tt.fieldSelector, got, tt.expected)
+		}
+	}
+}
+
+func TestSetDynamicColumns(t *testing.T) {
+	preserveUnknownFields := true
+
+	tests := []struct {
+		name     string
+		schema   v1.JSONSchemaProps
+		expected proto.ColumnType
+	}{
+		{"string", v1.JSONSchemaProps{Type: "string"}, proto.ColumnType_STRING},
+		{"date-time", v1.JSONSchemaProps{Type: "string", Format: "date-time"}, proto.ColumnType_TIMESTAMP},
+		{"integer", v1.JSONSchemaProps{Type: "integer"}, proto.ColumnType_INT},
+		{"number", v1.JSONSchemaProps{Type: "number"}, proto.ColumnType_DOUBLE},
+		{"boolean", v1.JSONSchemaProps{Type: "boolean"}, proto.ColumnType_BOOL},
+		{"int-or-string", v1.JSONSchemaProps{XIntOrString: true}, proto.ColumnType_STRING},
+		{"preserve-unknown-fields", v1.JSONSchemaProps{Type: "string", XPreserveUnknownFields: &preserveUnknownFields}, proto.ColumnType_JSON},
+		{"object", v1.JSONSchemaProps{Type: "object"}, proto.ColumnType_JSON},
+	}
+
+	for _, tt := range tests {
+		column := &plugin.Column{Name: tt.name}
+		setDynamicColumns(tt.schema, column)
+		if column.Type != tt.expected {
+			t.Errorf("setDynamicColumns(%s) = %v, expected %v", tt.name, column.Type, tt.expected)
+		}
+	}
+}
+
+func TestGetCustomResourceNestedColumns(t *testing.T) {
+	issuerRef := v1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]v1.JSONSchemaProps{
+			"name": {Type: "string"},
+			"kind": {Type: "string"},
+			"options": {
+				Type: "object",
+				Properties: map[string]v1.JSONSchemaProps{
+					"retries": {Type: "integer"},
+				},
+			},
+		},
+	}
+
+	var got []string
+	for _, column := range getCustomResourceNestedColumns("spec_issuer_ref", []string{"spec", "issuerRef"}, issuerRef, 3) {
+		got = append(got, column.Name)
+	}
+	expected := []string{"spec_issuer_ref_kind", "spec_issuer_ref_name", "spec_issuer_ref_options", "spec_issuer_ref_options_retries"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("getCustomResourceNestedColumns() = %v, expected %v", got, expected)
+	}
+
+	if columns := getCustomResourceNestedColumns("spec_issuer_ref", []string{"spec", "issuerRef"}, issuerRef, 1); len(columns) != 0 {
+		t.Errorf("expected no nested columns for depth 1, got %d", len(columns))
+	}
+}
+
+func TestEvaluateJSONPath(t *testing.T) {
+	object := map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": int64(3)},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+				map[string]interface{}{"type": "Issuing", "status": "False"},
+			},
+		},
+	}
+
+	tests := []struct {
+		path     string
+		expected interface{}
+	}{
+		{".spec.replicas", int64(3)},
+		{`.status.conditions[?(@.type=="Ready")].status`, "True"},
+		{".status.conditions[*].type", "Ready,Issuing"},
+		{".status.conditions[*]", `{"status":"True","type":"Ready"},{"status":"False","type":"Issuing"}`},
+		{".status.missing", nil},
+	}
+
+	for _, tt := range tests {
+		got, err := evaluateJSONPath(object, tt.path)
+		if err != nil {
+			t.Errorf("evaluateJSONPath(%s) failed: %v", tt.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("evaluateJSONPath(%s) = %v, expected %v", tt.path, got, tt.expected)
+		}
+	}
+}
@@ -220,7 +220,7 @@ func listK8sDaemonSets(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydra
 	pageLeft := true
 
 	for pageLeft {
-		response, err = clientset.AppsV1().DaemonSets(d.EqualsQualString("namespace")).List(ctx, input)
+		response, err = clientset.AppsV1().DaemonSets(getListNamespace(ctx, d)).List(ctx, input)
 		if err != nil {
 			return nil, err
 		}
@@ -232,6 +232,11 @@ func listK8sDaemonSets(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydra
 		}
 
 		for _, daemonSet := range response.Items {
+			// Skip the resources of the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, daemonSet.Namespace) {
+				continue
+			}
+
 			d.StreamListItem(ctx, DaemonSet{daemonSet, parsedContent{SourceType: "deployed"}})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -263,6 +268,11 @@ func getK8sDaemonSet(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 		return nil, nil
 	}
 
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, namespace) {
+		return nil, nil
+	}
+
 	// Get the manifest resource
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "DaemonSet")
 	if err != nil {
@@ -227,7 +227,7 @@ func listK8sDeployments(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydr
 
 	for pageLeft {
 
-		response, err = clientset.AppsV1().Deployments(d.EqualsQualString("namespace")).List(ctx, input)
+		response, err = clientset.AppsV1().Deployments(getListNamespace(ctx, d)).List(ctx, input)
 		if err != nil {
 			return nil, err
 		}
@@ -239,6 +239,11 @@ func listK8sDeployments(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydr
 		}
 
 		for _, item := range response.Items {
+			// Skip the resources of the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, item.Namespace) {
+				continue
+			}
+
 			d.StreamListItem(ctx, Deployment{item, parsedContent{SourceType: "deployed"}})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -270,6 +275,11 @@ func getK8sDeployment(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrat
 		return nil, nil
 	}
 
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, namespace) {
+		return nil, nil
+	}
+
 	// Get the manifest resource
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "Deployment")
 	if err != nil {
@@ -135,7 +135,7 @@ func listK8sEnpointSlices(ctx context.Context, d *plugin.QueryData, _ *plugin.Hy
 	pageLeft := true
 
 	for pageLeft {
-		response, err = clientset.DiscoveryV1().EndpointSlices(d.EqualsQualString("namespace")).List(ctx, input)
+		response, err = clientset.DiscoveryV1().EndpointSlices(getListNamespace(ctx, d)).List(ctx, input)
 		if err != nil {
 			return nil, err
 		}
@@ -147,6 +147,11 @@ func listK8sEnpointSlices(ctx context.Context, d *plugin.QueryData, _ *plugin.Hy
 		}
 
 		for _, endpointSlice := range response.Items {
+			// Skip the resources of the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, endpointSlice.Namespace) {
+				continue
+			}
+
 			d.StreamListItem(ctx, EndpointSlice{endpointSlice, parsedContent{SourceType: "deployed"}})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -178,6 +183,11 @@ func getK8sEnpointSlice(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydr
 		return nil, nil
 	}
 
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, namespace) {
+		return nil, nil
+	}
+
 	// Get the manifest resource
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "EndpointSlice")
 	if err != nil {
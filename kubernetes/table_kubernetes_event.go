@@ -181,7 +181,7 @@ func listK8sEvents(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDa
 	pageLeft := true
 
 	for pageLeft {
-		response, err = clientset.CoreV1().Events(d.EqualsQualString("namespace")).List(ctx, input)
+		response, err = clientset.CoreV1().Events(getListNamespace(ctx, d)).List(ctx, input)
 		if err != nil {
 			plugin.Logger(ctx).Error("listK8sEvents", "api_err", err)
 			return nil, err
@@ -194,6 +194,11 @@ func listK8sEvents(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDa
 		}
 
 		for _, event := range response.Items {
+			// Skip the resources of the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, event.Namespace) {
+				continue
+			}
+
 			d.StreamListItem(ctx, Event{event, parsedContent{SourceType: "deployed"}})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -224,6 +229,11 @@ func getK8sEvent(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 		return nil, nil
 	}
 
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, namespace) {
+		return nil, nil
+	}
+
 	// Get the manifest resource
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "Event")
 	if err != nil {
@@ -193,7 +193,7 @@ func listK8sHPAs(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 	pageLeft := true
 
 	for pageLeft {
-		response, err = clientset.AutoscalingV2().HorizontalPodAutoscalers(d.EqualsQualString("namespace")).List(ctx, input)
+		response, err = clientset.AutoscalingV2().HorizontalPodAutoscalers(getListNamespace(ctx, d)).List(ctx, input)
 		if err != nil {
 			plugin.Logger(ctx).Error("listK8sHPAs", "api_err", err)
 			return nil, err
@@ -206,6 +206,11 @@ func listK8sHPAs(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 		}
 
 		for _, hpa := range response.Items {
+			// Skip the resources of the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, hpa.Namespace) {
+				continue
+			}
+
 			d.StreamListItem(ctx, HorizontalPodAutoscaler{hpa, parsedContent{SourceType: "deployed"}})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -235,6 +240,11 @@ func getK8sHPA(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData)
 		return nil, nil
 	}
 
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, namespace) {
+		return nil, nil
+	}
+
 	// Get the manifest resource
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "HorizontalPodAutoscaler")
 	if err != nil {
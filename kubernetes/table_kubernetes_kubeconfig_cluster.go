@@ -0,0 +1,86 @@
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableKubernetesKubeconfigCluster(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "kubernetes_kubeconfig_cluster",
+		Description: "Clusters defined in the kubeconfig files of the connection.",
+		List: &plugin.ListConfig{
+			Hydrate: listK8sKubeconfigClusters,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "name", Require: plugin.Optional},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "The name of the cluster."},
+			{Name: "server", Type: proto.ColumnType_STRING, Description: "The URL of the API server of the cluster."},
+			{Name: "tls_server_name", Type: proto.ColumnType_STRING, Description: "The server name used to verify the certificate of the API server, if different from the host of the server URL.", Transform: transform.FromField("TLSServerName").Transform(transform.NullIfZeroValue)},
+			{Name: "insecure_skip_tls_verify", Type: proto.ColumnType_BOOL, Description: "True if the certificate of the API server is not verified.", Transform: transform.FromField("InsecureSkipTLSVerify")},
+			{Name: "has_certificate_authority", Type: proto.ColumnType_BOOL, Description: "True if a certificate authority is set to verify the certificate of the API server, as a file or inline data."},
+			{Name: "certificate_authority", Type: proto.ColumnType_STRING, Description: "The path of the certificate authority file, if any.", Transform: transform.FromField("CertificateAuthority").Transform(transform.NullIfZeroValue)},
+			{Name: "proxy_url", Type: proto.ColumnType_STRING, Description: "The URL of the proxy used to reach the API server, if any.", Transform: transform.FromField("ProxyURL").Transform(transform.NullIfZeroValue)},
+			{Name: "disable_compression", Type: proto.ColumnType_BOOL, Description: "True if the compression of the responses is disabled."},
+			{Name: "kubeconfig_path", Type: proto.ColumnType_STRING, Description: "The path of the kubeconfig file the cluster is defined in. Null for the inline and explicit configs.", Transform: transform.FromField("LocationOfOrigin").Transform(transform.NullIfZeroValue)},
+		},
+	}
+}
+
+type KubeconfigCluster struct {
+	Name                    string
+	Server                  string
+	TLSServerName           string
+	InsecureSkipTLSVerify   bool
+	HasCertificateAuthority bool
+	CertificateAuthority    string
+	ProxyURL                string
+	DisableCompression      bool
+	LocationOfOrigin        string
+}
+
+//// LIST FUNCTION
+
+func listK8sKubeconfigClusters(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	files, err := getKubeconfigFiles(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("listK8sKubeconfigClusters", "kubeconfig_error", err)
+		return nil, err
+	}
+
+	// No kubeconfig file is loaded if the deployed source is disabled
+	for _, file := range files {
+		for _, name := range sortedKubeconfigNames(file.Config.Clusters) {
+			if d.EqualsQualString("name") != "" && d.EqualsQualString("name") != name {
+				continue
+			}
+
+			cluster := file.Config.Clusters[name]
+			d.StreamListItem(ctx, KubeconfigCluster{
+				Name:                    name,
+				Server:                  cluster.Server,
+				TLSServerName:           cluster.TLSServerName,
+				InsecureSkipTLSVerify:   cluster.InsecureSkipTLSVerify,
+				HasCertificateAuthority: cluster.CertificateAuthority != "" || len(cluster.CertificateAuthorityData) > 0,
+				CertificateAuthority:    cluster.CertificateAuthority,
+				ProxyURL:                cluster.ProxyURL,
+				DisableCompression:      cluster.DisableCompression,
+				LocationOfOrigin:        file.Path,
+			})
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,84 @@
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableKubernetesKubeconfigContext(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "kubernetes_kubeconfig_context",
+		Description: "Contexts defined in the kubeconfig files of the connection.",
+		List: &plugin.ListConfig{
+			Hydrate: listK8sKubeconfigContexts,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "name", Require: plugin.Optional},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "The name of the context."},
+			{Name: "cluster", Type: proto.ColumnType_STRING, Description: "The name of the cluster of the context."},
+			{Name: "user", Type: proto.ColumnType_STRING, Description: "The name of the user of the context.", Transform: transform.FromField("AuthInfo")},
+			{Name: "namespace", Type: proto.ColumnType_STRING, Description: "The default namespace of the context, if any.", Transform: transform.FromField("Namespace").Transform(transform.NullIfZeroValue)},
+			{Name: "current", Type: proto.ColumnType_BOOL, Description: "True if the context is the one used by the connection."},
+			{Name: "kubeconfig_path", Type: proto.ColumnType_STRING, Description: "The path of the kubeconfig file the context is defined in. Null for the inline and explicit configs.", Transform: transform.FromField("LocationOfOrigin").Transform(transform.NullIfZeroValue)},
+		},
+	}
+}
+
+type KubeconfigContext struct {
+	Name             string
+	Cluster          string
+	AuthInfo         string
+	Namespace        string
+	Current          bool
+	LocationOfOrigin string
+}
+
+//// LIST FUNCTION
+
+func listK8sKubeconfigContexts(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	files, err := getKubeconfigFiles(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("listK8sKubeconfigContexts", "kubeconfig_error", err)
+		return nil, err
+	}
+
+	currentContext, _ := getCurrentContext(ctx, d, nil).(string)
+
+	// No kubeconfig file is loaded if the deployed source is disabled.
+	// The contexts defined in several files are merged by the clients, the first file defining a context winning.
+	seen := map[string]bool{}
+	for _, file := range files {
+		for _, name := range sortedKubeconfigNames(file.Config.Contexts) {
+			first := !seen[name]
+			seen[name] = true
+
+			if d.EqualsQualString("name") != "" && d.EqualsQualString("name") != name {
+				continue
+			}
+
+			kubeContext := file.Config.Contexts[name]
+			d.StreamListItem(ctx, KubeconfigContext{
+				Name:             name,
+				Cluster:          kubeContext.Cluster,
+				AuthInfo:         kubeContext.AuthInfo,
+				Namespace:        kubeContext.Namespace,
+				Current:          name == currentContext && first,
+				LocationOfOrigin: file.Path,
+			})
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,117 @@
+package kubernetes
+
+import (
+	"context"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableKubernetesKubeconfigUser(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "kubernetes_kubeconfig_user",
+		Description: "Users defined in the kubeconfig files of the connection, with their secrets redacted.",
+		List: &plugin.ListConfig{
+			Hydrate: listK8sKubeconfigUsers,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "name", Require: plugin.Optional},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "The name of the user."},
+			{Name: "auth_type", Type: proto.ColumnType_STRING, Description: "The authentication method of the user, i.e. exec, token, token_file, client_certificate, basic, none, or the name of the auth provider, e.g. oidc."},
+			{Name: "username", Type: proto.ColumnType_STRING, Description: "The username of the basic authentication, if any.", Transform: transform.FromField("Username").Transform(transform.NullIfZeroValue)},
+			{Name: "token_file", Type: proto.ColumnType_STRING, Description: "The path of the file holding the bearer token, if any.", Transform: transform.FromField("TokenFile").Transform(transform.NullIfZeroValue)},
+			{Name: "client_certificate", Type: proto.ColumnType_STRING, Description: "The path of the client certificate file, if any.", Transform: transform.FromField("ClientCertificate").Transform(transform.NullIfZeroValue)},
+			{Name: "client_key", Type: proto.ColumnType_STRING, Description: "The path of the client key file, if any.", Transform: transform.FromField("ClientKey").Transform(transform.NullIfZeroValue)},
+			{Name: "client_certificate_not_after", Type: proto.ColumnType_TIMESTAMP, Description: "The expiry of the client certificate, if any."},
+			{Name: "exec_command", Type: proto.ColumnType_STRING, Description: "The command of the exec credential plugin, e.g. aws or kubelogin.", Transform: transform.FromField("ExecCommand").Transform(transform.NullIfZeroValue)},
+			{Name: "exec_args", Type: proto.ColumnType_JSON, Description: "The arguments of the exec credential plugin."},
+			{Name: "exec_api_version", Type: proto.ColumnType_STRING, Description: "The API version of the ExecCredential returned by the exec credential plugin.", Transform: transform.FromField("ExecAPIVersion").Transform(transform.NullIfZeroValue)},
+			{Name: "exec_env", Type: proto.ColumnType_JSON, Description: "The environment variables set for the exec credential plugin, with their values redacted."},
+			{Name: "auth_provider_name", Type: proto.ColumnType_STRING, Description: "The name of the auth provider, e.g. oidc.", Transform: transform.FromField("AuthProviderName").Transform(transform.NullIfZeroValue)},
+			{Name: "auth_provider_config", Type: proto.ColumnType_JSON, Description: "The config of the auth provider, with the tokens and client secrets redacted."},
+			{Name: "impersonate", Type: proto.ColumnType_STRING, Description: "The user to impersonate, if any.", Transform: transform.FromField("Impersonate").Transform(transform.NullIfZeroValue)},
+			{Name: "kubeconfig_path", Type: proto.ColumnType_STRING, Description: "The path of the kubeconfig file the user is defined in. Null for the inline and explicit configs.", Transform: transform.FromField("LocationOfOrigin").Transform(transform.NullIfZeroValue)},
+		},
+	}
+}
+
+type KubeconfigUser struct {
+	Name                      string
+	AuthType                  string
+	Username                  string
+	TokenFile                 string
+	ClientCertificate         string
+	ClientKey                 string
+	ClientCertificateNotAfter *time.Time
+	ExecCommand               string
+	ExecArgs                  []string
+	ExecAPIVersion            string
+	ExecEnv                   map[string]string
+	AuthProviderName          string
+	AuthProviderConfig        map[string]string
+	Impersonate               string
+	LocationOfOrigin          string
+}
+
+//// LIST FUNCTION
+
+func listK8sKubeconfigUsers(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	files, err := getKubeconfigFiles(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("listK8sKubeconfigUsers", "kubeconfig_error", err)
+		return nil, err
+	}
+
+	// No kubeconfig file is loaded if the deployed source is disabled
+	for _, file := range files {
+		for _, name := range sortedKubeconfigNames(file.Config.AuthInfos) {
+			if d.EqualsQualString("name") != "" && d.EqualsQualString("name") != name {
+				continue
+			}
+
+			authInfo := file.Config.AuthInfos[name]
+			user := KubeconfigUser{
+				Name:              name,
+				AuthType:          getKubeconfigAuthType(authInfo),
+				Username:          authInfo.Username,
+				TokenFile:         authInfo.TokenFile,
+				ClientCertificate: authInfo.ClientCertificate,
+				ClientKey:         authInfo.ClientKey,
+				Impersonate:       authInfo.Impersonate,
+				LocationOfOrigin:  file.Path,
+			}
+
+			// A certificate which cannot be read does not prevent listing the other users
+			user.ClientCertificateNotAfter, err = getClientCertificateNotAfter(authInfo)
+			if err != nil {
+				plugin.Logger(ctx).Warn("listK8sKubeconfigUsers", "client_certificate_error", err, "user", name)
+			}
+
+			if authInfo.Exec != nil {
+				user.ExecCommand = authInfo.Exec.Command
+				user.ExecArgs = authInfo.Exec.Args
+				user.ExecAPIVersion = authInfo.Exec.APIVersion
+				user.ExecEnv = redactExecEnv(authInfo.Exec.Env)
+			}
+			if authInfo.AuthProvider != nil {
+				user.AuthProviderName = authInfo.AuthProvider.Name
+				user.AuthProviderConfig = redactAuthProviderConfig(authInfo.AuthProvider.Config)
+			}
+
+			d.StreamListItem(ctx, user)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
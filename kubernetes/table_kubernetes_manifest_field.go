@@ -0,0 +1,185 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	goYaml "gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+//// TABLE DEFINITION
+
+func tableKubernetesManifestField(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "kubernetes_manifest_field",
+		Description: "Fields of the resources defined in the manifest files, along with their exact line and column.",
+		List: &plugin.ListConfig{
+			Hydrate: listK8sManifestFields,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "path", Require: plugin.Optional},
+				{Name: "kind", Require: plugin.Optional},
+				{Name: "name", Require: plugin.Optional},
+				{Name: "namespace", Require: plugin.Optional},
+				{Name: "field_path", Require: plugin.Optional},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: "path", Type: proto.ColumnType_STRING, Description: "The path to the manifest file."},
+			{Name: "kind", Type: proto.ColumnType_STRING, Description: "The kind of the resource."},
+			{Name: "api_version", Type: proto.ColumnType_STRING, Description: "The API version of the resource.", Transform: transform.FromField("APIVersion").Transform(transform.NullIfZeroValue)},
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "The name of the resource.", Transform: transform.FromField("Name").Transform(transform.NullIfZeroValue)},
+			{Name: "namespace", Type: proto.ColumnType_STRING, Description: "The namespace of the resource, if set in the manifest.", Transform: transform.FromField("Namespace").Transform(transform.NullIfZeroValue)},
+			{Name: "field_path", Type: proto.ColumnType_STRING, Description: "The path of the field in the resource, e.g. spec.containers[0].securityContext.privileged. The keys containing dots are quoted between brackets, e.g. metadata.labels[\"app.kubernetes.io/name\"]."},
+			{Name: "keys", Type: proto.ColumnType_JSON, Description: "The array representation of the path of the field."},
+			{Name: "key", Type: proto.ColumnType_STRING, Description: "The key of the field in its parent, or its index for the items of an array."},
+			{Name: "value", Type: proto.ColumnType_JSON, Description: "The value of the field."},
+			{Name: "line", Type: proto.ColumnType_INT, Description: "The line number of the field. For the fields of an object, the line of the key, e.g. the line of `privileged: true`."},
+			{Name: "column", Type: proto.ColumnType_INT, Description: "The column number of the field."},
+			{Name: "start_line", Type: proto.ColumnType_INT, Description: "The line number where the resource starts.", Transform: transform.FromField("StartLine").NullIfZero()},
+			{Name: "end_line", Type: proto.ColumnType_INT, Description: "The line number where the resource ends.", Transform: transform.FromField("EndLine").NullIfZero()},
+			{Name: "source_type", Type: proto.ColumnType_STRING, Description: "The source of the resource. The value is always manifest."},
+		},
+	}
+}
+
+type ManifestField struct {
+	Path       string
+	Kind       string
+	APIVersion string
+	Name       string
+	Namespace  string
+	FieldPath  string
+	Keys       []string
+	Key        string
+	Value      interface{}
+	Line       int
+	Column     int
+	StartLine  int
+	EndLine    int
+	SourceType string
+}
+
+//// LIST FUNCTION
+
+func listK8sManifestFields(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	parsedContents, err := getParsedManifestFileContent(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// The secret data is returned as set in the secret_data_mode argument, as in the kubernetes_secret table
+	secretDataMode, err := getSecretDataMode(GetConfig(d.Connection))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, content := range parsedContents {
+		// The positions are not available for the files which cannot be parsed as a YAML node tree
+		if content.Node == nil {
+			continue
+		}
+
+		resource := ManifestField{
+			Path:       content.Path,
+			Kind:       content.Kind,
+			APIVersion: getManifestFieldString(content.Node, "apiVersion"),
+			Name:       getManifestFieldString(content.Node, "metadata.name"),
+			Namespace:  getManifestFieldString(content.Node, "metadata.namespace"),
+			StartLine:  content.StartLine,
+			EndLine:    content.EndLine,
+			SourceType: content.SourceType,
+		}
+
+		if !matchesManifestFieldQuals(d, resource) || !isNamespaceIncluded(d, parsedContentNamespace(content)) {
+			continue
+		}
+
+		// Look up the requested field only, if any
+		var fields []manifestField
+		if fieldPath := d.EqualsQualString("field_path"); fieldPath != "" {
+			if field, ok := findManifestField(content.Node, fieldPath); ok {
+				// Keep the path as requested, e.g. with a quoted key which does not need to be
+				field.FieldPath = fieldPath
+				fields = append(fields, field)
+			}
+		} else {
+			fields = getManifestFields(content.Node, nil, "")
+		}
+
+		for _, field := range fields {
+			// The values which cannot be decoded on their own, e.g. the aliases to the anchors of other fields, are left empty
+			value, err := decodeManifestFieldValue(field.Value)
+			if err != nil {
+				plugin.Logger(ctx).Warn("listK8sManifestFields", "failed to decode the field value", err, "path", content.Path, "field_path", field.FieldPath)
+			}
+
+			if isSecretObject(resource.APIVersion, resource.Kind) {
+				value = applySecretDataModeToField(field.Keys, value, secretDataMode)
+			}
+
+			item := resource
+			item.FieldPath = field.FieldPath
+			item.Keys = field.Keys
+			item.Key = field.Keys[len(field.Keys)-1]
+			item.Value = value
+			item.Line = field.Line
+			item.Column = field.Column
+			d.StreamListItem(ctx, item)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// matchesManifestFieldQuals checks the resource against the optional quals on the resource columns
+func matchesManifestFieldQuals(d *plugin.QueryData, resource ManifestField) bool {
+	quals := map[string]string{
+		"path":      resource.Path,
+		"kind":      resource.Kind,
+		"name":      resource.Name,
+		"namespace": resource.Namespace,
+	}
+	for column, value := range quals {
+		if d.EqualsQuals[column] != nil && d.EqualsQualString(column) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// getManifestFieldString returns the string value of the field at the given path, or an empty string if not found
+func getManifestFieldString(node *goYaml.Node, fieldPath string) string {
+	field, ok := findManifestField(node, fieldPath)
+	if !ok || field.Value.Kind != goYaml.ScalarNode {
+		return ""
+	}
+	return field.Value.Value
+}
+
+// decodeManifestFieldValue decodes the value of a field in the same way as the objects of the manifest files
+func decodeManifestFieldValue(node *goYaml.Node) (interface{}, error) {
+	content, err := goYaml.Marshal(node)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := yaml.ToJSON(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -154,6 +154,11 @@ func listK8sNamespaces(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydra
 		}
 
 		for _, namespace := range response.Items {
+			// Skip the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, namespace.Name) {
+				continue
+			}
+
 			d.StreamListItem(ctx, Namespace{namespace, parsedContent{SourceType: "deployed"}})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -184,6 +189,11 @@ func getK8sNamespace(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 		return nil, nil
 	}
 
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, name) {
+		return nil, nil
+	}
+
 	// Get the manifest resource
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "Namespace")
 	if err != nil {
@@ -145,7 +145,7 @@ func listK8sNetworkPolicies(ctx context.Context, d *plugin.QueryData, _ *plugin.
 	pageLeft := true
 
 	for pageLeft {
-		response, err = clientset.NetworkingV1().NetworkPolicies(d.EqualsQualString("namespace")).List(ctx, input)
+		response, err = clientset.NetworkingV1().NetworkPolicies(getListNamespace(ctx, d)).List(ctx, input)
 		if err != nil {
 			return nil, err
 		}
@@ -157,6 +157,11 @@ func listK8sNetworkPolicies(ctx context.Context, d *plugin.QueryData, _ *plugin.
 		}
 
 		for _, networkPolicy := range response.Items {
+			// Skip the resources of the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, networkPolicy.Namespace) {
+				continue
+			}
+
 			d.StreamListItem(ctx, NetworkPolicy{networkPolicy, parsedContent{SourceType: "deployed"}})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -188,6 +193,11 @@ func getK8sNetworkPolicy(ctx context.Context, d *plugin.QueryData, _ *plugin.Hyd
 		return nil, nil
 	}
 
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, namespace) {
+		return nil, nil
+	}
+
 	// Get the manifest resource
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "NetworkPolicy")
 	if err != nil {
@@ -189,7 +189,7 @@ func listK8sPVCs(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 	pageLeft := true
 
 	for pageLeft {
-		response, err = clientset.CoreV1().PersistentVolumeClaims(d.EqualsQualString("namespace")).List(ctx, input)
+		response, err = clientset.CoreV1().PersistentVolumeClaims(getListNamespace(ctx, d)).List(ctx, input)
 		if err != nil {
 			return nil, err
 		}
@@ -201,6 +201,11 @@ func listK8sPVCs(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 		}
 
 		for _, persistentVolumeClaim := range response.Items {
+			// Skip the resources of the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, persistentVolumeClaim.Namespace) {
+				continue
+			}
+
 			d.StreamListItem(ctx, PersistentVolumeClaim{persistentVolumeClaim, parsedContent{SourceType: "deployed"}})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -232,6 +237,11 @@ func getK8sPVC(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData)
 		return nil, nil
 	}
 
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, namespace) {
+		return nil, nil
+	}
+
 	// Get the manifest resource
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "PersistentVolumeClaim")
 	if err != nil {
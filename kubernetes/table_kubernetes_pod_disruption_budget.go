@@ -141,7 +141,7 @@ func listPDBs(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (
 	pageLeft := true
 
 	for pageLeft {
-		response, err = clientset.PolicyV1().PodDisruptionBudgets(d.EqualsQualString("namespace")).List(ctx, input)
+		response, err = clientset.PolicyV1().PodDisruptionBudgets(getListNamespace(ctx, d)).List(ctx, input)
 		if err != nil {
 			return nil, err
 		}
@@ -153,6 +153,11 @@ func listPDBs(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (
 		}
 
 		for _, item := range response.Items {
+			// Skip the resources of the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, item.Namespace) {
+				continue
+			}
+
 			d.StreamListItem(ctx, PodDisruptionBudget{item, parsedContent{SourceType: "deployed"}})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -184,6 +189,11 @@ func getPDB(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (in
 		return nil, nil
 	}
 
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, namespace) {
+		return nil, nil
+	}
+
 	// Get the manifest resource
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "PodDisruptionBudget")
 	if err != nil {
@@ -125,7 +125,7 @@ func listK8sPodTemplates(ctx context.Context, d *plugin.QueryData, _ *plugin.Hyd
 	pageLeft := true
 
 	for pageLeft {
-		response, err = clientset.CoreV1().PodTemplates(d.EqualsQualString("namespace")).List(ctx, input)
+		response, err = clientset.CoreV1().PodTemplates(getListNamespace(ctx, d)).List(ctx, input)
 		if err != nil {
 			return nil, err
 		}
@@ -137,6 +137,11 @@ func listK8sPodTemplates(ctx context.Context, d *plugin.QueryData, _ *plugin.Hyd
 		}
 
 		for _, podTemplate := range response.Items {
+			// Skip the resources of the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, podTemplate.Namespace) {
+				continue
+			}
+
 			d.StreamListItem(ctx, PodTemplate{podTemplate, parsedContent{SourceType: "deployed"}})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -168,6 +173,11 @@ func getK8sPodTemplate(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydra
 		return nil, nil
 	}
 
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, namespace) {
+		return nil, nil
+	}
+
 	// Get the manifest resource
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "PodTemplate")
 	if err != nil {
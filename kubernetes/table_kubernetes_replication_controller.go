@@ -190,7 +190,7 @@ func listK8sReplicaControllers(ctx context.Context, d *plugin.QueryData, _ *plug
 	pageLeft := true
 
 	for pageLeft {
-		response, err = clientset.CoreV1().ReplicationControllers(d.EqualsQualString("namespace")).List(ctx, input)
+		response, err = clientset.CoreV1().ReplicationControllers(getListNamespace(ctx, d)).List(ctx, input)
 		if err != nil {
 			return nil, err
 		}
@@ -202,6 +202,11 @@ func listK8sReplicaControllers(ctx context.Context, d *plugin.QueryData, _ *plug
 		}
 
 		for _, replicaController := range response.Items {
+			// Skip the resources of the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, replicaController.Namespace) {
+				continue
+			}
+
 			d.StreamListItem(ctx, ReplicationController{replicaController, parsedContent{SourceType: "deployed"}})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -233,6 +238,11 @@ func getK8sReplicaController(ctx context.Context, d *plugin.QueryData, _ *plugin
 		return nil, nil
 	}
 
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, namespace) {
+		return nil, nil
+	}
+
 	// Get the manifest resource
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "ReplicationController")
 	if err != nil {
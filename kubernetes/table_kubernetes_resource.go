@@ -0,0 +1,232 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func tableKubernetesResource(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "kubernetes_resource",
+		Description: "Kubernetes resources of any listable API resource served by the cluster, selected by the resource name, API group and API version.",
+		List: &plugin.ListConfig{
+			Hydrate: listK8sResources,
+			KeyColumns: getOptionalKeyQualWithCommonKeyQuals([]*plugin.KeyColumn{
+				{Name: "resource", Require: plugin.Required},
+				{Name: "api_group", Require: plugin.Optional},
+				{Name: "api_version", Require: plugin.Optional},
+				{Name: "label_selector", Require: plugin.Optional, CacheMatch: "exact"},
+			}),
+		},
+		Columns: []*plugin.Column{
+			{Name: "resource", Type: proto.ColumnType_STRING, Description: "The plural name of the API resource, e.g. deployments."},
+			{Name: "api_group", Type: proto.ColumnType_STRING, Description: "The API group of the resource. Empty for the core group.", Transform: transform.FromField("APIGroup")},
+			{Name: "api_version", Type: proto.ColumnType_STRING, Description: "The API version of the resource, including the group, e.g. apps/v1. Defaults to the preferred version of the API group.", Transform: transform.FromField("APIVersion")},
+			{Name: "kind", Type: proto.ColumnType_STRING, Description: "The kind of the resource, e.g. Deployment.", Transform: transform.FromField("Object").Transform(unstructuredFieldValue("kind"))},
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "Name of the object. Name must be unique within a namespace.", Transform: transform.FromField("Object").Transform(unstructuredFieldValue("metadata", "name"))},
+			{Name: "namespace", Type: proto.ColumnType_STRING, Description: "Namespace defines the space within which each name must be unique. Empty for the cluster scoped resources.", Transform: transform.FromField("Object").Transform(unstructuredFieldValue("metadata", "namespace"))},
+			{Name: "uid", Type: proto.ColumnType_STRING, Description: "UID is the unique in time and space value for this object.", Transform: transform.FromField("Object").Transform(unstructuredFieldValue("metadata", "uid"))},
+			{Name: "creation_timestamp", Type: proto.ColumnType_TIMESTAMP, Description: "CreationTimestamp is a timestamp representing the server time when this object was created.", Transform: transform.FromField("Object").Transform(unstructuredFieldValue("metadata", "creationTimestamp"))},
+			{Name: "labels", Type: proto.ColumnType_JSON, Description: "Map of string keys and values that can be used to organize and categorize (scope and select) objects.", Transform: transform.FromField("Object").Transform(unstructuredFieldValue("metadata", "labels"))},
+			{Name: "annotations", Type: proto.ColumnType_JSON, Description: "Annotations is an unstructured key value map stored with a resource that may be set by external tools to store and retrieve arbitrary metadata.", Transform: transform.FromField("Object").Transform(unstructuredFieldValue("metadata", "annotations"))},
+			{Name: "spec", Type: proto.ColumnType_JSON, Description: "The desired state of the resource, if any.", Transform: transform.FromField("Object").Transform(unstructuredFieldValue("spec"))},
+			{Name: "status", Type: proto.ColumnType_JSON, Description: "The observed state of the resource, if any.", Transform: transform.FromField("Object").Transform(unstructuredFieldValue("status"))},
+			{Name: "object", Type: proto.ColumnType_JSON, Description: "The full object, as returned by the API server.", Transform: transform.FromField("Object")},
+			{Name: "label_selector", Type: proto.ColumnType_STRING, Description: "A label selector string to restrict the list of returned objects by their labels.", Transform: transform.FromQual("label_selector")},
+			{Name: "context_name", Type: proto.ColumnType_STRING, Description: "Kubectl config context name.", Transform: transform.FromField("ContextName").Transform(transform.NullIfZeroValue)},
+		},
+	}
+}
+
+type Resource struct {
+	Resource    string
+	APIGroup    string
+	APIVersion  string
+	Object      map[string]interface{}
+	ContextName string
+}
+
+//// HYDRATE FUNCTIONS
+
+func listK8sResources(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	clientset, err := GetNewClientDynamic(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("listK8sResources", "connection_error", err)
+		return nil, err
+	}
+
+	// The resources are only fetched from the cluster
+	if clientset == nil {
+		return nil, nil
+	}
+
+	resources, err := getK8sAPIResources(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("listK8sResources", "discovery_error", err)
+		return nil, err
+	}
+
+	var apiGroup *string
+	if d.EqualsQuals["api_group"] != nil {
+		group := d.EqualsQualString("api_group")
+		apiGroup = &group
+	}
+
+	apiResource, ok := findListableAPIResource(resources, d.EqualsQualString("resource"), apiGroup, d.EqualsQualString("api_version"))
+	if !ok {
+		return nil, nil
+	}
+
+	namespace := d.EqualsQualString("namespace")
+
+	// Cluster scoped resources do not belong to any namespace
+	if !apiResource.Namespaced && namespace != "" {
+		return nil, nil
+	}
+
+	resourceId := schema.GroupVersionResource{
+		Group:    apiResource.Group,
+		Version:  apiResource.Version,
+		Resource: apiResource.Name,
+	}
+
+	input := metav1.ListOptions{
+		Limit:         500,
+		LabelSelector: d.EqualsQualString("label_selector"),
+		FieldSelector: strings.Join(getCommonOptionalKeyQualsValueForFieldSelector(d), ","),
+	}
+
+	// Limiting the results
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < input.Limit {
+			if *limit < 1 {
+				input.Limit = 1
+			} else {
+				input.Limit = *limit
+			}
+		}
+	}
+
+	currentContext, _ := getCurrentContext(ctx, d, nil).(string)
+
+	// The secret data is returned as set in the secret_data_mode argument, as in the kubernetes_secret table
+	isSecret := apiResource.Group == "" && apiResource.Name == "secrets"
+	secretDataMode := SecretDataModeFull
+	if isSecret {
+		secretDataMode, err = getSecretDataMode(GetConfig(d.Connection))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The namespaced resources are listed from the single namespace included in the connection, if any
+	if apiResource.Namespaced {
+		namespace = getListNamespace(ctx, d)
+	}
+
+	pageLeft := true
+	for pageLeft {
+		response, err := clientset.Resource(resourceId).Namespace(namespace).List(ctx, input)
+		if err != nil {
+			// Handle not found error code
+			if isNotFoundError(err) {
+				return nil, nil
+			}
+			plugin.Logger(ctx).Error("listK8sResources", "api_error", err)
+			return nil, fmt.Errorf("failed to list %s: %v", resourceId.String(), err)
+		}
+
+		if response.GetContinue() != "" {
+			input.Continue = response.GetContinue()
+		} else {
+			pageLeft = false
+		}
+
+		for _, item := range response.Items {
+			// Skip the resources of the namespaces which are not included in the connection, and these namespaces themselves
+			itemNamespace := item.GetNamespace()
+			if apiResource.Group == "" && apiResource.Name == "namespaces" {
+				itemNamespace = item.GetName()
+			}
+			if !isNamespaceIncluded(d, itemNamespace) {
+				continue
+			}
+
+			if isSecret {
+				applySecretDataModeToObject(item.Object, secretDataMode)
+			}
+
+			d.StreamListItem(ctx, Resource{
+				Resource:    apiResource.Name,
+				APIGroup:    apiResource.Group,
+				APIVersion:  apiResource.APIVersion,
+				Object:      item.Object,
+				ContextName: currentContext,
+			})
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// findListableAPIResource returns the discovered resource with the given name, which supports the list verb.
+// The API group and version are optional, and the preferred version of the group is used if none is given.
+func findListableAPIResource(resources []APIResource, name string, group *string, apiVersion string) (APIResource, bool) {
+	var found *APIResource
+	for i, resource := range resources {
+		if resource.Name != name || !slices.Contains(resource.Verbs, "list") {
+			continue
+		}
+		if group != nil && *group != resource.Group {
+			continue
+		}
+		if apiVersion != "" && apiVersion != resource.APIVersion {
+			continue
+		}
+
+		if resource.PreferredVersion {
+			return resource, true
+		}
+		if found == nil {
+			found = &resources[i]
+		}
+	}
+
+	if found == nil {
+		return APIResource{}, false
+	}
+	return *found, true
+}
+
+//// TRANSFORM FUNCTIONS
+
+// unstructuredFieldValue returns a transform extracting the nested field at the given path of an unstructured object
+func unstructuredFieldValue(fields ...string) transform.TransformFunc {
+	return func(_ context.Context, d *transform.TransformData) (interface{}, error) {
+		object, ok := d.Value.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+
+		value, found, err := unstructured.NestedFieldNoCopy(object, fields...)
+		if err != nil || !found {
+			return nil, nil
+		}
+		return value, nil
+	}
+}
@@ -153,7 +153,7 @@ func listK8sResourceQuotas(ctx context.Context, d *plugin.QueryData, h *plugin.H
 	pageLeft := true
 
 	for pageLeft {
-		response, err = clientset.CoreV1().ResourceQuotas(d.EqualsQualString("namespace")).List(ctx, input)
+		response, err = clientset.CoreV1().ResourceQuotas(getListNamespace(ctx, d)).List(ctx, input)
 		if err != nil {
 			return nil, err
 		}
@@ -165,6 +165,11 @@ func listK8sResourceQuotas(ctx context.Context, d *plugin.QueryData, h *plugin.H
 		}
 
 		for _, resourceQuota := range response.Items {
+			// Skip the resources of the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, resourceQuota.Namespace) {
+				continue
+			}
+
 			d.StreamListItem(ctx, ResourceQuota{resourceQuota, parsedContent{SourceType: "deployed"}})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -195,6 +200,11 @@ func getK8sResourceQuota(ctx context.Context, d *plugin.QueryData, _ *plugin.Hyd
 		return nil, nil
 	}
 
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, namespace) {
+		return nil, nil
+	}
+
 	// Get the manifest resource
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "ResourceQuota")
 	if err != nil {
@@ -0,0 +1,45 @@
+package kubernetes
+
+import (
+	"testing"
+)
+
+func TestFindListableAPIResource(t *testing.T) {
+	resources := []APIResource{
+		{Name: "events", Group: "", Version: "v1", APIVersion: "v1", PreferredVersion: true, Verbs: []string{"get", "list"}},
+		{Name: "events", Group: "events.k8s.io", Version: "v1", APIVersion: "events.k8s.io/v1", PreferredVersion: true, Verbs: []string{"get", "list"}},
+		{Name: "horizontalpodautoscalers", Group: "autoscaling", Version: "v1", APIVersion: "autoscaling/v1", Verbs: []string{"list"}},
+		{Name: "horizontalpodautoscalers", Group: "autoscaling", Version: "v2", APIVersion: "autoscaling/v2", PreferredVersion: true, Verbs: []string{"list"}},
+		{Name: "tokenreviews", Group: "authentication.k8s.io", Version: "v1", APIVersion: "authentication.k8s.io/v1", PreferredVersion: true, Verbs: []string{"create"}},
+	}
+
+	coreGroup := ""
+	eventsGroup := "events.k8s.io"
+
+	tests := []struct {
+		name       string
+		resource   string
+		group      *string
+		apiVersion string
+		expected   string
+	}{
+		{"first group", "events", nil, "", "v1"},
+		{"core group", "events", &coreGroup, "", "v1"},
+		{"named group", "events", &eventsGroup, "", "events.k8s.io/v1"},
+		{"preferred version", "horizontalpodautoscalers", nil, "", "autoscaling/v2"},
+		{"explicit version", "horizontalpodautoscalers", nil, "autoscaling/v1", "autoscaling/v1"},
+		{"unknown version", "horizontalpodautoscalers", nil, "autoscaling/v3", ""},
+		{"not listable", "tokenreviews", nil, "", ""},
+		{"unknown resource", "widgets", nil, "", ""},
+	}
+
+	for _, tt := range tests {
+		var got string
+		if resource, ok := findListableAPIResource(resources, tt.resource, tt.group, tt.apiVersion); ok {
+			got = resource.APIVersion
+		}
+		if got != tt.expected {
+			t.Errorf("%s: findListableAPIResource() = %q, expected %q", tt.name, got, tt.expected)
+		}
+	}
+}
@@ -42,12 +42,20 @@ func tableKubernetesSecret(ctx context.Context) *plugin.Table {
 			{
 				Name:        "data",
 				Type:        proto.ColumnType_JSON,
-				Description: "Contains the secret data.",
+				Description: "Contains the secret data. The values are base64 encoded, or redacted, hashed or null depending on the secret_data_mode argument of the connection.",
+				Transform:   transform.From(transformSecretData),
 			},
 			{
 				Name:        "string_data",
 				Type:        proto.ColumnType_JSON,
-				Description: "Contains the configuration binary data.",
+				Description: "Contains the configuration binary data. The values are redacted, hashed or null depending on the secret_data_mode argument of the connection.",
+				Transform:   transform.From(transformSecretStringData),
+			},
+			{
+				Name:        "data_keys",
+				Type:        proto.ColumnType_JSON,
+				Description: "The keys of the secret data and string data.",
+				Transform:   transform.From(transformSecretDataKeys),
 			},
 			{
 				Name:        "context_name",
@@ -81,6 +89,14 @@ func tableKubernetesSecret(ctx context.Context) *plugin.Table {
 type Secret struct {
 	v1.Secret
 	parsedContent
+	DataMode string
+}
+
+// newSecret returns the row of the given secret, with the secret_data_mode applied to the last applied configuration.
+// The data and string data columns are transformed when read.
+func newSecret(secret v1.Secret, content parsedContent, mode string) Secret {
+	secret.Annotations = applySecretDataModeToAnnotations(secret.Annotations, mode)
+	return Secret{secret, content, mode}
 }
 
 //// HYDRATE FUNCTIONS
@@ -96,6 +112,11 @@ func listK8sSecrets(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateD
 		return nil, err
 	}
 
+	mode, err := getSecretDataMode(GetConfig(d.Connection))
+	if err != nil {
+		return nil, err
+	}
+
 	// Check for manifest files
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "Secret")
 	if err != nil {
@@ -105,7 +126,7 @@ func listK8sSecrets(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateD
 	for _, content := range parsedContents {
 		secret := content.ParsedData.(*v1.Secret)
 
-		d.StreamListItem(ctx, Secret{*secret, content})
+		d.StreamListItem(ctx, newSecret(*secret, content, mode))
 
 		// Context can be cancelled due to manual cancellation or the limit has been hit
 		if d.RowsRemaining(ctx) == 0 {
@@ -148,7 +169,7 @@ func listK8sSecrets(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateD
 	pageLeft := true
 
 	for pageLeft {
-		response, err = clientset.CoreV1().Secrets(d.EqualsQualString("namespace")).List(ctx, input)
+		response, err = clientset.CoreV1().Secrets(getListNamespace(ctx, d)).List(ctx, input)
 		if err != nil {
 			return nil, err
 		}
@@ -160,7 +181,12 @@ func listK8sSecrets(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateD
 		}
 
 		for _, secret := range response.Items {
-			d.StreamListItem(ctx, Secret{secret, parsedContent{SourceType: "deployed"}})
+			// Skip the resources of the namespaces which are not included in the connection
+			if !isNamespaceIncluded(d, secret.Namespace) {
+				continue
+			}
+
+			d.StreamListItem(ctx, newSecret(secret, parsedContent{SourceType: "deployed"}, mode))
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
 			if d.RowsRemaining(ctx) == 0 {
@@ -183,6 +209,11 @@ func getK8sSecret(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDat
 		return nil, err
 	}
 
+	mode, err := getSecretDataMode(GetConfig(d.Connection))
+	if err != nil {
+		return nil, err
+	}
+
 	name := d.EqualsQuals["name"].GetStringValue()
 	namespace := d.EqualsQuals["namespace"].GetStringValue()
 
@@ -191,6 +222,11 @@ func getK8sSecret(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDat
 		return nil, nil
 	}
 
+	// return if the namespace is not included in the connection
+	if !isNamespaceIncluded(d, namespace) {
+		return nil, nil
+	}
+
 	// Get the manifest resource
 	parsedContents, err := fetchResourceFromManifestFileByKind(ctx, d, "Secret")
 	if err != nil {
@@ -201,7 +237,7 @@ func getK8sSecret(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDat
 		secret := content.ParsedData.(*v1.Secret)
 
 		if secret.Name == name && secret.Namespace == namespace {
-			return Secret{*secret, content}, nil
+			return newSecret(*secret, content, mode), nil
 		}
 	}
 
@@ -215,7 +251,7 @@ func getK8sSecret(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDat
 		return nil, err
 	}
 
-	return Secret{*secret, parsedContent{SourceType: "deployed"}}, nil
+	return newSecret(*secret, parsedContent{SourceType: "deployed"}, mode), nil
 }
 
 func getSecretResourceContext(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
@@ -239,6 +275,32 @@ func getSecretResourceContext(ctx context.Context, d *plugin.QueryData, h *plugi
 
 //// TRANSFORM FUNCTIONS
 
+func transformSecretData(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	obj := d.HydrateItem.(Secret)
+	if obj.DataMode == SecretDataModeFull {
+		return obj.Data, nil
+	}
+	return secretDataValues(obj.Data, obj.DataMode), nil
+}
+
+func transformSecretStringData(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	obj := d.HydrateItem.(Secret)
+	if obj.DataMode == SecretDataModeFull || obj.StringData == nil {
+		return obj.StringData, nil
+	}
+
+	data := map[string][]byte{}
+	for key, value := range obj.StringData {
+		data[key] = []byte(value)
+	}
+	return secretDataValues(data, obj.DataMode), nil
+}
+
+func transformSecretDataKeys(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	obj := d.HydrateItem.(Secret)
+	return secretDataKeys(obj.Data, obj.StringData), nil
+}
+
 func transformSecretTags(_ context.Context, d *transform.TransformData) (interface{}, error) {
 	obj := d.HydrateItem.(Secret)
 	return mergeTags(obj.Labels, obj.Annotations), nil
@@ -0,0 +1,387 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+//// TABLE DEFINITION
+
+func tableKubernetesUpgradeReadiness(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "kubernetes_upgrade_readiness",
+		Description: "Blockers of the upgrade of the cluster to a target version, e.g. the version skew of the nodes, the removed APIs in use and the pod disruption budgets blocking the node drains.",
+		List: &plugin.ListConfig{
+			Hydrate: listK8sUpgradeReadiness,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "target_version", Require: plugin.Required},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: "target_version", Type: proto.ColumnType_STRING, Description: "The Kubernetes version to upgrade to, e.g. v1.31 or 1.31.0.", Transform: transform.FromQual("target_version")},
+			{Name: "category", Type: proto.ColumnType_STRING, Description: "The category of the blocker, i.e. control_plane, node, removed_api, pod_security_policy or pod_disruption_budget."},
+			{Name: "severity", Type: proto.ColumnType_STRING, Description: "The severity of the blocker, i.e. high for the blockers of the upgrade, medium for the blockers of the node drains, and low for the removed APIs served but not known to be used."},
+			{Name: "kind", Type: proto.ColumnType_STRING, Description: "The kind of the resource blocking the upgrade, e.g. Node.", Transform: transform.FromField("Kind").Transform(transform.NullIfZeroValue)},
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "The name of the resource blocking the upgrade, if any.", Transform: transform.FromField("Name").Transform(transform.NullIfZeroValue)},
+			{Name: "namespace", Type: proto.ColumnType_STRING, Description: "The namespace of the resource blocking the upgrade, if any.", Transform: transform.FromField("Namespace").Transform(transform.NullIfZeroValue)},
+			{Name: "api_version", Type: proto.ColumnType_STRING, Description: "The removed API version, for the removed APIs.", Transform: transform.FromField("APIVersion").Transform(transform.NullIfZeroValue)},
+			{Name: "removed_release", Type: proto.ColumnType_STRING, Description: "The Kubernetes version removing the API version, for the removed APIs.", Transform: transform.FromField("RemovedRelease").Transform(transform.NullIfZeroValue)},
+			{Name: "current_version", Type: proto.ColumnType_STRING, Description: "The current version of the control plane, kubelet or kube-proxy, if any.", Transform: transform.FromField("CurrentVersion").Transform(transform.NullIfZeroValue)},
+			{Name: "reason", Type: proto.ColumnType_STRING, Description: "Why the upgrade is blocked."},
+			{Name: "source_type", Type: proto.ColumnType_STRING, Description: "The source of the resource blocking the upgrade, e.g. deployed or manifest."},
+			{Name: "path", Type: proto.ColumnType_STRING, Description: "The path of the file defining the resource, if any.", Transform: transform.FromField("Path").Transform(transform.NullIfZeroValue)},
+			{Name: "context_name", Type: proto.ColumnType_STRING, Description: "Kubectl config context name.", Transform: transform.FromField("ContextName").Transform(transform.NullIfZeroValue)},
+		},
+	}
+}
+
+type UpgradeBlocker struct {
+	Category       string
+	Severity       string
+	Kind           string
+	Name           string
+	Namespace      string
+	APIVersion     string
+	RemovedRelease string
+	CurrentVersion string
+	Reason         string
+	SourceType     string
+	Path           string
+	ContextName    string
+}
+
+//// LIST FUNCTION
+
+func listK8sUpgradeReadiness(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	target, err := version.ParseGeneric(d.EqualsQualString("target_version"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid target_version %q: %w", d.EqualsQualString("target_version"), err)
+	}
+
+	var blockers []UpgradeBlocker
+
+	// The cluster checks, for the deployed resources only
+	clientset, err := GetNewClientset(ctx, d)
+	if err != nil {
+		logger.Error("listK8sUpgradeReadiness", "client_error", err)
+		return nil, err
+	}
+	if clientset != nil {
+		for _, check := range []func(context.Context, *plugin.QueryData, *version.Version) ([]UpgradeBlocker, error){
+			getControlPlaneUpgradeBlockers,
+			getNodeUpgradeBlockers,
+			getServedRemovedAPIUpgradeBlockers,
+			getPodSecurityPolicyUpgradeBlockers,
+			getPodDisruptionBudgetUpgradeBlockers,
+		} {
+			checkBlockers, err := check(ctx, d, target)
+			if err != nil {
+				logger.Error("listK8sUpgradeReadiness", "check_error", err)
+				return nil, err
+			}
+			blockers = append(blockers, checkBlockers...)
+		}
+	}
+
+	// The resources of the manifest files, Helm charts and releases, and snapshots
+	parsedContents, err := getAllParsedContent(ctx, d)
+	if err != nil {
+		logger.Error("listK8sUpgradeReadiness", "parse_error", err)
+		return nil, err
+	}
+	blockers = append(blockers, getParsedContentUpgradeBlockers(d, parsedContents, target)...)
+
+	currentContext, _ := getCurrentContext(ctx, d, nil).(string)
+
+	for _, blocker := range blockers {
+		blocker.ContextName = currentContext
+		d.StreamListItem(ctx, blocker)
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// getControlPlaneUpgradeBlockers checks that the control plane can be upgraded to the target version in one step
+func getControlPlaneUpgradeBlockers(ctx context.Context, d *plugin.QueryData, target *version.Version) ([]UpgradeBlocker, error) {
+	client, err := getDiscoveryClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	serverVersion, err := client.ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	server, err := version.ParseGeneric(serverVersion.GitVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if reason := checkControlPlaneUpgrade(server, target); reason != "" {
+		return []UpgradeBlocker{{
+			Category:       "control_plane",
+			Severity:       "high",
+			CurrentVersion: serverVersion.GitVersion,
+			Reason:         reason,
+			SourceType:     "deployed",
+		}}, nil
+	}
+	return nil, nil
+}
+
+// getNodeUpgradeBlockers checks the version skew of the kubelet and kube-proxy of the nodes with the target version
+func getNodeUpgradeBlockers(ctx context.Context, d *plugin.QueryData, target *version.Version) ([]UpgradeBlocker, error) {
+	clientset, err := GetNewClientset(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	input := metav1.ListOptions{
+		Limit: 500,
+	}
+
+	var blockers []UpgradeBlocker
+	var response *v1.NodeList
+	pageLeft := true
+
+	for pageLeft {
+		response, err = clientset.CoreV1().Nodes().List(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.GetContinue() != "" {
+			input.Continue = response.Continue
+		} else {
+			pageLeft = false
+		}
+
+		for _, node := range response.Items {
+			// The kube-proxy version is no longer reported by the recent kubelets
+			for _, component := range [][2]string{
+				{"kubelet", node.Status.NodeInfo.KubeletVersion},
+				{"kube-proxy", node.Status.NodeInfo.KubeProxyVersion},
+			} {
+				componentVersion := component[1]
+				if reason := checkComponentVersionSkew(component[0], componentVersion, target); reason != "" {
+					blockers = append(blockers, UpgradeBlocker{
+						Category:       "node",
+						Severity:       "high",
+						Kind:           "Node",
+						Name:           node.Name,
+						CurrentVersion: componentVersion,
+						Reason:         reason,
+						SourceType:     "deployed",
+					})
+				}
+			}
+		}
+	}
+
+	return blockers, nil
+}
+
+// getServedRemovedAPIUpgradeBlockers checks the API versions served by the cluster which are removed by the target version.
+// The APIs requested since the API server started are reported by the apiserver_requested_deprecated_apis metric, which requires
+// the permission to get the /metrics endpoint. The other removed APIs are served but not known to be used.
+func getServedRemovedAPIUpgradeBlockers(ctx context.Context, d *plugin.QueryData, target *version.Version) ([]UpgradeBlocker, error) {
+	clientset, err := GetNewClientset(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var blockers []UpgradeBlocker
+	requested := map[string]bool{}
+
+	metrics, err := clientset.Discovery().RESTClient().Get().AbsPath("/metrics").DoRaw(ctx)
+	if err != nil {
+		plugin.Logger(ctx).Warn("getServedRemovedAPIUpgradeBlockers", "failed to get the metrics of the API server", err)
+	}
+	for _, request := range parseDeprecatedAPIRequests(metrics) {
+		if request.RemovedRelease == "" || request.Subresource != "" || !isRemovedBy(request.RemovedRelease, target) {
+			continue
+		}
+		requested[request.APIVersion()+"/"+request.Resource] = true
+
+		blockers = append(blockers, UpgradeBlocker{
+			Category:       "removed_api",
+			Severity:       "high",
+			Name:           request.Resource,
+			APIVersion:     request.APIVersion(),
+			RemovedRelease: request.RemovedRelease,
+			Reason:         fmt.Sprintf("%s %s is requested by a client, and removed in v%s", request.APIVersion(), request.Resource, request.RemovedRelease),
+			SourceType:     "deployed",
+		})
+	}
+
+	resources, err := getK8sAPIResources(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range resources {
+		api := findRemovedAPI(resource.APIVersion, "", resource.Name, target)
+		if api == nil || requested[resource.APIVersion+"/"+resource.Name] {
+			continue
+		}
+
+		blockers = append(blockers, UpgradeBlocker{
+			Category:       "removed_api",
+			Severity:       "low",
+			Kind:           resource.Kind,
+			Name:           resource.Name,
+			APIVersion:     resource.APIVersion,
+			RemovedRelease: api.RemovedRelease,
+			Reason:         fmt.Sprintf("%s %s is served, and removed in v%s. Check that no client uses it.", resource.APIVersion, resource.Name, api.RemovedRelease),
+			SourceType:     "deployed",
+		})
+	}
+
+	return blockers, nil
+}
+
+// getPodSecurityPolicyUpgradeBlockers checks the pod security policies, which are removed in 1.25 without replacement in the same API
+func getPodSecurityPolicyUpgradeBlockers(ctx context.Context, d *plugin.QueryData, target *version.Version) ([]UpgradeBlocker, error) {
+	api := findRemovedAPI("policy/v1beta1", "PodSecurityPolicy", "", target)
+	if api == nil {
+		return nil, nil
+	}
+
+	// The pod security policies can only be listed while served
+	resources, err := getK8sAPIResources(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	served := false
+	for _, resource := range resources {
+		if resource.APIVersion == api.APIVersion && resource.Name == api.Resource {
+			served = true
+		}
+	}
+	if !served {
+		return nil, nil
+	}
+
+	dynamicClient, err := GetNewClientDynamic(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	response, err := dynamicClient.Resource(schema.GroupVersionResource{Group: "policy", Version: "v1beta1", Resource: api.Resource}).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var blockers []UpgradeBlocker
+	for _, psp := range response.Items {
+		blockers = append(blockers, UpgradeBlocker{
+			Category:       "pod_security_policy",
+			Severity:       "high",
+			Kind:           api.Kind,
+			Name:           psp.GetName(),
+			APIVersion:     api.APIVersion,
+			RemovedRelease: api.RemovedRelease,
+			Reason:         fmt.Sprintf("the pod security policies are removed in v%s, migrate to the pod security admission first", api.RemovedRelease),
+			SourceType:     "deployed",
+		})
+	}
+	return blockers, nil
+}
+
+// getPodDisruptionBudgetUpgradeBlockers checks the pod disruption budgets which do not allow any disruption, and so block the node drains
+func getPodDisruptionBudgetUpgradeBlockers(ctx context.Context, d *plugin.QueryData, _ *version.Version) ([]UpgradeBlocker, error) {
+	clientset, err := GetNewClientset(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	input := metav1.ListOptions{
+		Limit: 500,
+	}
+
+	var blockers []UpgradeBlocker
+	var response *policyv1.PodDisruptionBudgetList
+	pageLeft := true
+
+	for pageLeft {
+		response, err = clientset.PolicyV1().PodDisruptionBudgets(getListNamespace(ctx, d)).List(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.GetContinue() != "" {
+			input.Continue = response.Continue
+		} else {
+			pageLeft = false
+		}
+
+		for _, pdb := range response.Items {
+			if !isNamespaceIncluded(d, pdb.Namespace) || pdb.Status.ExpectedPods == 0 || pdb.Status.DisruptionsAllowed > 0 {
+				continue
+			}
+
+			blockers = append(blockers, UpgradeBlocker{
+				Category:   "pod_disruption_budget",
+				Severity:   "medium",
+				Kind:       "PodDisruptionBudget",
+				Name:       pdb.Name,
+				Namespace:  pdb.Namespace,
+				Reason:     fmt.Sprintf("the pod disruption budget allows no disruption of its %d pods, and would block the node drains", pdb.Status.ExpectedPods),
+				SourceType: "deployed",
+			})
+		}
+	}
+
+	return blockers, nil
+}
+
+// getParsedContentUpgradeBlockers checks the resources of the manifest files, Helm charts and releases, and snapshots
+// which use an API version removed by the target version
+func getParsedContentUpgradeBlockers(d *plugin.QueryData, parsedContents []parsedContent, target *version.Version) []UpgradeBlocker {
+	var blockers []UpgradeBlocker
+	for _, content := range parsedContents {
+		obj, ok := content.ParsedData.(runtime.Object)
+		if !ok || !isNamespaceIncluded(d, parsedContentNamespace(content)) {
+			continue
+		}
+
+		apiVersion, kind := obj.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
+		api := findRemovedAPI(apiVersion, kind, "", target)
+		if api == nil {
+			continue
+		}
+
+		blocker := UpgradeBlocker{
+			Category:       "removed_api",
+			Severity:       "high",
+			Kind:           kind,
+			APIVersion:     apiVersion,
+			RemovedRelease: api.RemovedRelease,
+			Reason:         fmt.Sprintf("%s %s is removed in v%s", apiVersion, kind, api.RemovedRelease),
+			SourceType:     content.SourceType,
+			Path:           content.Path,
+		}
+		if accessor, err := meta.Accessor(obj); err == nil {
+			blocker.Name = accessor.GetName()
+			blocker.Namespace = accessor.GetNamespace()
+		}
+		blockers = append(blockers, blocker)
+	}
+	return blockers
+}
@@ -0,0 +1,218 @@
+package kubernetes
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// Utils functions for the kubernetes_upgrade_readiness table
+
+// removedAPI is an API version of a resource removed from Kubernetes, as listed in the deprecated API migration guide
+type removedAPI struct {
+	APIVersion     string
+	Kind           string
+	Resource       string
+	RemovedRelease string
+}
+
+// removedAPIs are the API versions removed since Kubernetes 1.16. See https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var removedAPIs = []removedAPI{
+	{APIVersion: "extensions/v1beta1", Kind: "DaemonSet", Resource: "daemonsets", RemovedRelease: "1.16"},
+	{APIVersion: "extensions/v1beta1", Kind: "Deployment", Resource: "deployments", RemovedRelease: "1.16"},
+	{APIVersion: "extensions/v1beta1", Kind: "NetworkPolicy", Resource: "networkpolicies", RemovedRelease: "1.16"},
+	{APIVersion: "extensions/v1beta1", Kind: "PodSecurityPolicy", Resource: "podsecuritypolicies", RemovedRelease: "1.16"},
+	{APIVersion: "extensions/v1beta1", Kind: "ReplicaSet", Resource: "replicasets", RemovedRelease: "1.16"},
+	{APIVersion: "apps/v1beta1", Kind: "Deployment", Resource: "deployments", RemovedRelease: "1.16"},
+	{APIVersion: "apps/v1beta1", Kind: "StatefulSet", Resource: "statefulsets", RemovedRelease: "1.16"},
+	{APIVersion: "apps/v1beta2", Kind: "DaemonSet", Resource: "daemonsets", RemovedRelease: "1.16"},
+	{APIVersion: "apps/v1beta2", Kind: "Deployment", Resource: "deployments", RemovedRelease: "1.16"},
+	{APIVersion: "apps/v1beta2", Kind: "ReplicaSet", Resource: "replicasets", RemovedRelease: "1.16"},
+	{APIVersion: "apps/v1beta2", Kind: "StatefulSet", Resource: "statefulsets", RemovedRelease: "1.16"},
+
+	{APIVersion: "admissionregistration.k8s.io/v1beta1", Kind: "MutatingWebhookConfiguration", Resource: "mutatingwebhookconfigurations", RemovedRelease: "1.22"},
+	{APIVersion: "admissionregistration.k8s.io/v1beta1", Kind: "ValidatingWebhookConfiguration", Resource: "validatingwebhookconfigurations", RemovedRelease: "1.22"},
+	{APIVersion: "apiextensions.k8s.io/v1beta1", Kind: "CustomResourceDefinition", Resource: "customresourcedefinitions", RemovedRelease: "1.22"},
+	{APIVersion: "apiregistration.k8s.io/v1beta1", Kind: "APIService", Resource: "apiservices", RemovedRelease: "1.22"},
+	{APIVersion: "certificates.k8s.io/v1beta1", Kind: "CertificateSigningRequest", Resource: "certificatesigningrequests", RemovedRelease: "1.22"},
+	{APIVersion: "coordination.k8s.io/v1beta1", Kind: "Lease", Resource: "leases", RemovedRelease: "1.22"},
+	{APIVersion: "extensions/v1beta1", Kind: "Ingress", Resource: "ingresses", RemovedRelease: "1.22"},
+	{APIVersion: "networking.k8s.io/v1beta1", Kind: "Ingress", Resource: "ingresses", RemovedRelease: "1.22"},
+	{APIVersion: "networking.k8s.io/v1beta1", Kind: "IngressClass", Resource: "ingressclasses", RemovedRelease: "1.22"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRole", Resource: "clusterroles", RemovedRelease: "1.22"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRoleBinding", Resource: "clusterrolebindings", RemovedRelease: "1.22"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "Role", Resource: "roles", RemovedRelease: "1.22"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "RoleBinding", Resource: "rolebindings", RemovedRelease: "1.22"},
+	{APIVersion: "scheduling.k8s.io/v1beta1", Kind: "PriorityClass", Resource: "priorityclasses", RemovedRelease: "1.22"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "CSIDriver", Resource: "csidrivers", RemovedRelease: "1.22"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "CSINode", Resource: "csinodes", RemovedRelease: "1.22"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "StorageClass", Resource: "storageclasses", RemovedRelease: "1.22"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "VolumeAttachment", Resource: "volumeattachments", RemovedRelease: "1.22"},
+
+	{APIVersion: "batch/v1beta1", Kind: "CronJob", Resource: "cronjobs", RemovedRelease: "1.25"},
+	{APIVersion: "discovery.k8s.io/v1beta1", Kind: "EndpointSlice", Resource: "endpointslices", RemovedRelease: "1.25"},
+	{APIVersion: "events.k8s.io/v1beta1", Kind: "Event", Resource: "events", RemovedRelease: "1.25"},
+	{APIVersion: "autoscaling/v2beta1", Kind: "HorizontalPodAutoscaler", Resource: "horizontalpodautoscalers", RemovedRelease: "1.25"},
+	{APIVersion: "node.k8s.io/v1beta1", Kind: "RuntimeClass", Resource: "runtimeclasses", RemovedRelease: "1.25"},
+	{APIVersion: "policy/v1beta1", Kind: "PodDisruptionBudget", Resource: "poddisruptionbudgets", RemovedRelease: "1.25"},
+	{APIVersion: "policy/v1beta1", Kind: "PodSecurityPolicy", Resource: "podsecuritypolicies", RemovedRelease: "1.25"},
+
+	{APIVersion: "autoscaling/v2beta2", Kind: "HorizontalPodAutoscaler", Resource: "horizontalpodautoscalers", RemovedRelease: "1.26"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta1", Kind: "FlowSchema", Resource: "flowschemas", RemovedRelease: "1.26"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta1", Kind: "PriorityLevelConfiguration", Resource: "prioritylevelconfigurations", RemovedRelease: "1.26"},
+
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "CSIStorageCapacity", Resource: "csistoragecapacities", RemovedRelease: "1.27"},
+
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta2", Kind: "FlowSchema", Resource: "flowschemas", RemovedRelease: "1.29"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta2", Kind: "PriorityLevelConfiguration", Resource: "prioritylevelconfigurations", RemovedRelease: "1.29"},
+
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta3", Kind: "FlowSchema", Resource: "flowschemas", RemovedRelease: "1.32"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta3", Kind: "PriorityLevelConfiguration", Resource: "prioritylevelconfigurations", RemovedRelease: "1.32"},
+}
+
+// findRemovedAPI returns the removal of the given API version and kind, or resource, by the target version, if any
+func findRemovedAPI(apiVersion string, kind string, resource string, target *version.Version) *removedAPI {
+	for i, api := range removedAPIs {
+		if api.APIVersion != apiVersion || (kind != "" && api.Kind != kind) || (resource != "" && api.Resource != resource) {
+			continue
+		}
+		if isRemovedBy(api.RemovedRelease, target) {
+			return &removedAPIs[i]
+		}
+	}
+	return nil
+}
+
+// isRemovedBy checks if the given release, e.g. 1.25, is the target version or an older version
+func isRemovedBy(removedRelease string, target *version.Version) bool {
+	removed, err := version.ParseGeneric(removedRelease)
+	if err != nil {
+		return false
+	}
+	return removed.Major() < target.Major() || (removed.Major() == target.Major() && removed.Minor() <= target.Minor())
+}
+
+// checkControlPlaneUpgrade returns the reason the control plane cannot be upgraded from the server version to the target version
+// in one step, if any. The control plane is upgraded one minor version at a time, and cannot be downgraded.
+func checkControlPlaneUpgrade(server *version.Version, target *version.Version) string {
+	if server.Major() != target.Major() {
+		return fmt.Sprintf("the control plane cannot be upgraded from v%d.%d to another major version", server.Major(), server.Minor())
+	}
+	if target.Minor() < server.Minor() {
+		return fmt.Sprintf("the control plane cannot be downgraded from v%d.%d", server.Major(), server.Minor())
+	}
+	if target.Minor() > server.Minor()+1 {
+		return fmt.Sprintf("the control plane is upgraded one minor version at a time, i.e. to v%d.%d first", server.Major(), server.Minor()+1)
+	}
+	return ""
+}
+
+// maxComponentVersionSkew returns the number of minor versions the kubelet and kube-proxy may be older than the API server at the
+// given version. See https://kubernetes.io/releases/version-skew-policy/
+func maxComponentVersionSkew(target *version.Version) int {
+	// The supported skew was extended from 2 to 3 minor versions in Kubernetes 1.28
+	if target.Major() > 1 || (target.Major() == 1 && target.Minor() >= 28) {
+		return 3
+	}
+	return 2
+}
+
+// checkComponentVersionSkew returns the reason a node component, i.e. the kubelet or kube-proxy, is not supported with the control plane
+// at the target version, if any. The components must not be newer than the control plane, nor older than the supported skew.
+func checkComponentVersionSkew(component string, componentVersion string, target *version.Version) string {
+	current, err := version.ParseGeneric(componentVersion)
+	if err != nil {
+		return ""
+	}
+	if current.Major() > target.Major() || (current.Major() == target.Major() && current.Minor() > target.Minor()) {
+		return fmt.Sprintf("%s %s is newer than the target version, which is not supported", component, componentVersion)
+	}
+	maxSkew := maxComponentVersionSkew(target)
+	if current.Major() != target.Major() || int(target.Minor())-int(current.Minor()) > maxSkew {
+		return fmt.Sprintf("%s %s is more than %d minor versions older than the target version, upgrade the node first", component, componentVersion, maxSkew)
+	}
+	return ""
+}
+
+// deprecatedAPIRequest is a deprecated API requested since the API server started, as reported by the
+// apiserver_requested_deprecated_apis metric
+type deprecatedAPIRequest struct {
+	Group          string
+	Version        string
+	Resource       string
+	Subresource    string
+	RemovedRelease string
+}
+
+// APIVersion returns the API version of the request, including the group
+func (r deprecatedAPIRequest) APIVersion() string {
+	if r.Group == "" {
+		return r.Version
+	}
+	return r.Group + "/" + r.Version
+}
+
+// parseDeprecatedAPIRequests returns the deprecated APIs requested, from the metrics of the API server in the Prometheus text format
+func parseDeprecatedAPIRequests(metrics []byte) []deprecatedAPIRequest {
+	var requests []deprecatedAPIRequest
+
+	scanner := bufio.NewScanner(bytes.NewReader(metrics))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "apiserver_requested_deprecated_apis{") {
+			continue
+		}
+
+		// e.g. apiserver_requested_deprecated_apis{group="policy",removed_release="1.25",resource="podsecuritypolicies",subresource="",version="v1beta1"} 1
+		end := strings.LastIndex(line, "}")
+		if end < 0 {
+			continue
+		}
+		if value, err := strconv.ParseFloat(strings.TrimSpace(line[end+1:]), 64); err != nil || value == 0 {
+			continue
+		}
+
+		labels := parseMetricLabels(line[len("apiserver_requested_deprecated_apis{"):end])
+		requests = append(requests, deprecatedAPIRequest{
+			Group:          labels["group"],
+			Version:        labels["version"],
+			Resource:       labels["resource"],
+			Subresource:    labels["subresource"],
+			RemovedRelease: labels["removed_release"],
+		})
+	}
+
+	return requests
+}
+
+// parseMetricLabels parses the labels of a metric sample, e.g. `group="policy",version="v1beta1"`
+func parseMetricLabels(labels string) map[string]string {
+	parsed := map[string]string{}
+	for labels != "" {
+		eq := strings.Index(labels, "=")
+		if eq < 0 || eq+1 >= len(labels) || labels[eq+1] != '"' {
+			break
+		}
+		name := strings.TrimSpace(labels[:eq])
+
+		// The values are quoted, with the quotes and backslashes escaped
+		value, rest, ok := strings.Cut(labels[eq+2:], `"`)
+		for ok && strings.HasSuffix(value, `\`) && !strings.HasSuffix(value, `\\`) {
+			var next string
+			next, rest, ok = strings.Cut(rest, `"`)
+			value += `"` + next
+		}
+		if unquoted, err := strconv.Unquote(`"` + value + `"`); err == nil {
+			value = unquoted
+		}
+		parsed[name] = value
+
+		labels = strings.TrimPrefix(rest, ",")
+	}
+	return parsed
+}
@@ -0,0 +1,92 @@
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+func TestCheckControlPlaneUpgrade(t *testing.T) {
+	tests := []struct {
+		server   string
+		target   string
+		expected bool
+	}{
+		{server: "v1.29.4", target: "v1.29", expected: false},
+		{server: "v1.29.4-eks-036c24b", target: "1.30.0", expected: false},
+		{server: "v1.29.4", target: "v1.31", expected: true},
+		{server: "v1.29.4", target: "v1.28", expected: true},
+	}
+	for _, tt := range tests {
+		reason := checkControlPlaneUpgrade(version.MustParseGeneric(tt.server), version.MustParseGeneric(tt.target))
+		if (reason != "") != tt.expected {
+			t.Errorf("checkControlPlaneUpgrade(%s, %s) = %q, expected a blocker: %v", tt.server, tt.target, reason, tt.expected)
+		}
+	}
+}
+
+func TestCheckComponentVersionSkew(t *testing.T) {
+	tests := []struct {
+		version  string
+		target   string
+		expected bool
+	}{
+		{version: "v1.31.1", target: "v1.31", expected: false},
+		{version: "v1.28.9-eks-ae9a62a", target: "v1.31", expected: false},
+		{version: "v1.27.3", target: "v1.31", expected: true},
+		{version: "", target: "v1.31", expected: false},
+
+		// The supported skew is 2 minor versions before Kubernetes 1.28
+		{version: "v1.25.6", target: "v1.27", expected: false},
+		{version: "v1.24.9", target: "v1.27", expected: true},
+		{version: "v1.25.6", target: "v1.28", expected: false},
+
+		// The components cannot be newer than the control plane
+		{version: "v1.32.0", target: "v1.31", expected: true},
+		{version: "v2.0.0", target: "v1.31", expected: true},
+	}
+	for _, tt := range tests {
+		reason := checkComponentVersionSkew("kubelet", tt.version, version.MustParseGeneric(tt.target))
+		if (reason != "") != tt.expected {
+			t.Errorf("checkComponentVersionSkew(%q, %s) = %q, expected a blocker: %v", tt.version, tt.target, reason, tt.expected)
+		}
+	}
+}
+
+func TestFindRemovedAPI(t *testing.T) {
+	if api := findRemovedAPI("policy/v1beta1", "PodSecurityPolicy", "", version.MustParseGeneric("v1.25")); api == nil || api.RemovedRelease != "1.25" {
+		t.Errorf("findRemovedAPI(policy/v1beta1 PodSecurityPolicy, 1.25) = %v, expected the removal in 1.25", api)
+	}
+	if api := findRemovedAPI("policy/v1beta1", "PodSecurityPolicy", "", version.MustParseGeneric("v1.24")); api != nil {
+		t.Errorf("findRemovedAPI(policy/v1beta1 PodSecurityPolicy, 1.24) = %v, expected nil", api)
+	}
+	if api := findRemovedAPI("storage.k8s.io/v1beta1", "", "csistoragecapacities", version.MustParseGeneric("v1.26")); api != nil {
+		t.Errorf("findRemovedAPI(storage.k8s.io/v1beta1 csistoragecapacities, 1.26) = %v, expected nil", api)
+	}
+	if api := findRemovedAPI("apps/v1", "Deployment", "", version.MustParseGeneric("v1.31")); api != nil {
+		t.Errorf("findRemovedAPI(apps/v1 Deployment) = %v, expected nil", api)
+	}
+}
+
+func TestParseDeprecatedAPIRequests(t *testing.T) {
+	metrics := []byte(`# HELP apiserver_requested_deprecated_apis [STABLE] Gauge of deprecated APIs that have been requested, broken out by API group, version, resource, subresource, and removed_release.
+# TYPE apiserver_requested_deprecated_apis gauge
+apiserver_requested_deprecated_apis{group="policy",removed_release="1.25",resource="podsecuritypolicies",subresource="",version="v1beta1"} 1
+apiserver_requested_deprecated_apis{group="",removed_release="",resource="componentstatuses",subresource="",version="v1"} 1
+apiserver_requested_deprecated_apis{group="batch",removed_release="1.25",resource="cronjobs",subresource="",version="v1beta1"} 0
+apiserver_request_total{code="200",verb="GET"} 12
+`)
+
+	expected := []deprecatedAPIRequest{
+		{Group: "policy", Version: "v1beta1", Resource: "podsecuritypolicies", RemovedRelease: "1.25"},
+		{Group: "", Version: "v1", Resource: "componentstatuses"},
+	}
+	got := parseDeprecatedAPIRequests(metrics)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("parseDeprecatedAPIRequests() = %+v, expected %+v", got, expected)
+	}
+	if got[1].APIVersion() != "v1" || got[0].APIVersion() != "policy/v1beta1" {
+		t.Errorf("APIVersion() = %q, %q; expected policy/v1beta1, v1", got[0].APIVersion(), got[1].APIVersion())
+	}
+}
@@ -11,21 +11,26 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	goYaml "gopkg.in/yaml.v3"
 
 	apiextension "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/azure"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 
@@ -45,16 +50,18 @@ var yamlDocSeparator = regexp.MustCompile(`(?m)^---\s*$`)
 type SourceType string
 
 const (
-	Deployed SourceType = "deployed"
-	Helm     SourceType = "helm"
-	Manifest SourceType = "manifest"
-	All      SourceType = "all"
+	Deployed              SourceType = "deployed"
+	Helm                  SourceType = "helm"
+	HelmReleaseSourceType SourceType = "helm_release"
+	Manifest              SourceType = "manifest"
+	Snapshot              SourceType = "snapshot"
+	All                   SourceType = "all"
 )
 
 // Validate the source type.
 func (sourceType SourceType) IsValid() error {
 	switch sourceType {
-	case Deployed, Helm, Manifest, All:
+	case Deployed, Helm, HelmReleaseSourceType, Manifest, Snapshot, All:
 		return nil
 	}
 	return fmt.Errorf("invalid source type: %s", sourceType)
@@ -65,7 +72,22 @@ func (sourceType SourceType) String() string {
 	return string(sourceType)
 }
 
-// ToSourceTypes is used to convert SourceType to []string
+// getSourceTypes returns the source types enabled in the connection config, set with source_types or the deprecated source_type.
+// Defaults to the deployed, helm and manifest sources, i.e. the helm_release and snapshot sources must be explicitly enabled.
+func getSourceTypes(kubernetesConfig kubernetesConfig) []string {
+	if kubernetesConfig.SourceTypes != nil {
+		return kubernetesConfig.SourceTypes
+	}
+	// TODO: Remove once `SourceType` is obsolete
+	if kubernetesConfig.SourceType != nil && *kubernetesConfig.SourceType != "all" {
+		return []string{*kubernetesConfig.SourceType}
+	}
+	return All.ToSourceTypes()
+}
+
+// ToSourceTypes is used to convert SourceType to []string.
+// The helm_release source type queries the cluster for the release manifests, and must be explicitly enabled.
+// The snapshot source type must be explicitly enabled as well, since it is usually used instead of the cluster.
 func (sourceType SourceType) ToSourceTypes() []string {
 	if sourceType == All {
 		return []string{Deployed.String(), Helm.String(), Manifest.String()}
@@ -97,50 +119,25 @@ func GetNewClientset(ctx context.Context, d *plugin.QueryData) (*kubernetes.Clie
 	}
 
 	// Get a rest.Config from the kubeconfig file.
-	restconfig, err := kubeconfig.ClientConfig()
+	restconfig, err := getRestConfig(ctx, d.ConnectionCache, kubeconfig, GetConfig(d.Connection))
 	if err != nil {
-		// if .kube/config file is not available check for inClusterConfig
-		configErr := err
-		if strings.Contains(err.Error(), ".kube/config: no such file or directory") {
-			clientset, err := inClusterConfig(ctx)
-			if err != nil {
-				return nil, errors.New(configErr.Error() + ", " + err.Error())
-			}
-
-			// save clientset in cache
-			d.ConnectionManager.Cache.Set(serviceCacheKey, clientset)
-
-			return clientset, nil
-		}
-
 		return nil, err
 	}
 
-	clientset, err := kubernetes.NewForConfig(restconfig)
+	httpClient, err := getHTTPClient(ctx, d.ConnectionCache, restconfig, GetConfig(d.Connection))
 	if err != nil {
 		return nil, err
 	}
 
-	// save clientset in cache
-	d.ConnectionManager.Cache.Set(serviceCacheKey, clientset)
-
-	return clientset, err
-}
-
-func inClusterConfig(ctx context.Context) (*kubernetes.Clientset, error) {
-	clusterConfig, err := rest.InClusterConfig()
+	clientset, err := kubernetes.NewForConfigAndClient(restconfig, httpClient)
 	if err != nil {
-		plugin.Logger(ctx).Error("InClusterConfig", "InClusterConfig", err)
 		return nil, err
 	}
 
-	clientset, err := kubernetes.NewForConfig(clusterConfig)
-	if err != nil {
-		plugin.Logger(ctx).Error("InClusterConfig", "NewForConfig", err)
-		return nil, err
-	}
+	// save clientset in cache
+	d.ConnectionManager.Cache.Set(serviceCacheKey, clientset)
 
-	return clientset, nil
+	return clientset, err
 }
 
 // GetNewClientCRD :: gets client for querying k8s apis for CustomResourceDefinition
@@ -164,26 +161,17 @@ func GetNewClientCRD(ctx context.Context, d *plugin.QueryData) (*apiextension.Cl
 	}
 
 	// Get a rest.Config from the kubeconfig file.
-	restconfig, err := kubeconfig.ClientConfig()
+	restconfig, err := getRestConfig(ctx, d.ConnectionCache, kubeconfig, GetConfig(d.Connection))
 	if err != nil {
-		// if .kube/config file is not available check for inClusterConfig
-		configErr := err
-		if strings.Contains(err.Error(), ".kube/config: no such file or directory") {
-			clientset, err := inClusterConfigCRD(ctx)
-			if err != nil {
-				return nil, errors.New(configErr.Error() + ", " + err.Error())
-			}
-
-			// save clientset in cache
-			d.ConnectionManager.Cache.Set(serviceCacheKey, clientset)
-
-			return clientset, nil
-		}
+		return nil, err
+	}
 
+	httpClient, err := getHTTPClient(ctx, d.ConnectionCache, restconfig, GetConfig(d.Connection))
+	if err != nil {
 		return nil, err
 	}
 
-	clientset, err := apiextension.NewForConfig(restconfig)
+	clientset, err := apiextension.NewForConfigAndClient(restconfig, httpClient)
 	if err != nil {
 		plugin.Logger(ctx).Error("GetNewClientCRD", "NewForConfig", err)
 		return nil, err
@@ -218,30 +206,17 @@ func GetNewClientCRDRaw(ctx context.Context, cc *connection.ConnectionCache, c *
 	}
 
 	// Get a rest.Config from the kubeconfig file.
-	restconfig, err := kubeconfig.ClientConfig()
+	restconfig, err := getRestConfig(ctx, cc, kubeconfig, GetConfig(c))
 	if err != nil {
-		// if .kube/config file is not available check for inClusterConfig
-		configErr := err
-		if strings.Contains(err.Error(), ".kube/config: no such file or directory") {
-			clientset, err := inClusterConfigCRD(ctx)
-			if err != nil {
-				return nil, errors.New(configErr.Error() + ", " + err.Error())
-			}
-
-			// save clientset in cache
-			cacheErr := cc.Set(ctx, serviceCacheKey, clientset)
-			if cacheErr != nil {
-				plugin.Logger(ctx).Error("inClusterConfigCRD", "cache-set", cacheErr)
-				return nil, err
-			}
-
-			return clientset, nil
-		}
+		return nil, err
+	}
 
+	httpClient, err := getHTTPClient(ctx, cc, restconfig, GetConfig(c))
+	if err != nil {
 		return nil, err
 	}
 
-	clientset, err := apiextension.NewForConfig(restconfig)
+	clientset, err := apiextension.NewForConfigAndClient(restconfig, httpClient)
 	if err != nil {
 		return nil, err
 	}
@@ -256,22 +231,6 @@ func GetNewClientCRDRaw(ctx context.Context, cc *connection.ConnectionCache, c *
 	return clientset, nil
 }
 
-func inClusterConfigCRD(ctx context.Context) (*apiextension.Clientset, error) {
-	clusterConfig, err := rest.InClusterConfig()
-	if err != nil {
-		plugin.Logger(ctx).Error("inClusterConfigCRD", "InClusterConfig", err)
-		return nil, err
-	}
-
-	clientset, err := apiextension.NewForConfig(clusterConfig)
-	if err != nil {
-		plugin.Logger(ctx).Error("inClusterConfigCRD", "NewForConfig", err)
-		return nil, err
-	}
-
-	return clientset, nil
-}
-
 // GetNewClientDynamic :: gets client for querying k8s apis for Dynamic Interface
 func GetNewClientDynamic(ctx context.Context, d *plugin.QueryData) (dynamic.Interface, error) {
 	// have we already created and cached the session?
@@ -291,50 +250,153 @@ func GetNewClientDynamic(ctx context.Context, d *plugin.QueryData) (dynamic.Inte
 	}
 
 	// Get a rest.Config from the kubeconfig file.
+	restconfig, err := getRestConfig(ctx, d.ConnectionCache, kubeconfig, GetConfig(d.Connection))
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := getHTTPClient(ctx, d.ConnectionCache, restconfig, GetConfig(d.Connection))
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := dynamic.NewForConfigAndClient(restconfig, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	// save clientset in cache
+	d.ConnectionManager.Cache.Set(serviceCacheKey, clientset)
+
+	return clientset, err
+}
+
+// getRestConfig returns the rest.Config built from the kubeconfig, along with the settings of the connection config, e.g. the impersonation,
+// the exec plugin settings or the rate limiting.
+// If the kubeconfig file is not available, the service account Kubernetes gives to pods is used.
+func getRestConfig(ctx context.Context, cc *connection.ConnectionCache, kubeconfig clientcmd.ClientConfig, kubernetesConfig kubernetesConfig) (*rest.Config, error) {
 	restconfig, err := kubeconfig.ClientConfig()
 	if err != nil {
 		// if .kube/config file is not available check for inClusterConfig
 		configErr := err
-		if strings.Contains(err.Error(), ".kube/config: no such file or directory") {
-			clientset, err := inClusterConfigCRDDynamic(ctx)
-			if err != nil {
-				return nil, errors.New(configErr.Error() + ", " + err.Error())
-			}
-
-			// save clientset in cache
-			d.ConnectionManager.Cache.Set(serviceCacheKey, clientset)
+		if !isMissingKubeconfigError(err) {
+			return nil, err
+		}
 
-			return clientset, nil
+		restconfig, err = rest.InClusterConfig()
+		if err != nil {
+			plugin.Logger(ctx).Error("getRestConfig", "InClusterConfig", err)
+			return nil, errors.New(configErr.Error() + ", " + err.Error())
 		}
+	}
 
+	if err := applyRequestSettings(restconfig, kubernetesConfig); err != nil {
 		return nil, err
 	}
+	restconfig.RateLimiter = getRateLimiter(ctx, cc, restconfig)
 
-	clientset, err := dynamic.NewForConfig(restconfig)
+	impersonate, err := getImpersonationConfig(kubernetesConfig)
 	if err != nil {
 		return nil, err
 	}
+	if impersonate.UserName != "" {
+		restconfig.Impersonate = impersonate
+	}
 
-	// save clientset in cache
-	d.ConnectionManager.Cache.Set(serviceCacheKey, clientset)
+	// Applied last, since the exec plugins returning a client certificate fall back to a copy of the config
+	if err := applyExecSettings(restconfig, kubernetesConfig); err != nil {
+		return nil, err
+	}
 
-	return clientset, err
+	return restconfig, nil
 }
 
-func inClusterConfigCRDDynamic(ctx context.Context) (dynamic.Interface, error) {
-	clusterConfig, err := rest.InClusterConfig()
-	if err != nil {
-		plugin.Logger(ctx).Error("inClusterConfigCRDDynamic", "InClusterConfig", err)
+// isMissingKubeconfigError checks if the kubeconfig could not be loaded since the default kubeconfig file does not exist,
+// in which case the service account Kubernetes gives to pods is used instead
+func isMissingKubeconfigError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), ".kube/config: no such file or directory")
+}
+
+// getImpersonationConfig returns the user, groups, uid and extra fields to impersonate, as configured in the connection config
+func getImpersonationConfig(kubernetesConfig kubernetesConfig) (rest.ImpersonationConfig, error) {
+	impersonate := rest.ImpersonationConfig{
+		Groups: kubernetesConfig.ImpersonateGroups,
+		Extra:  kubernetesConfig.ImpersonateExtra,
+	}
+	if kubernetesConfig.ImpersonateUser != nil {
+		impersonate.UserName = *kubernetesConfig.ImpersonateUser
+	}
+	if kubernetesConfig.ImpersonateUID != nil {
+		impersonate.UID = *kubernetesConfig.ImpersonateUID
+	}
+
+	// The API server only allows impersonating the groups, uid and extra fields of an impersonated user
+	if impersonate.UserName == "" && (len(impersonate.Groups) > 0 || impersonate.UID != "" || len(impersonate.Extra) > 0) {
+		return rest.ImpersonationConfig{}, errors.New("impersonate_groups, impersonate_uid and impersonate_extra require impersonate_user to be set")
+	}
+
+	return impersonate, nil
+}
+
+// discoveryCacheTTL is how long the API discovery of the cluster is cached, so that the CRDs and the API groups
+// installed or removed while the plugin is running are eventually picked up
+const discoveryCacheTTL = 5 * time.Minute
+
+// discoveryClients holds the discovery client and the REST mapper sharing its cache, so that they expire together
+type discoveryClients struct {
+	client discovery.CachedDiscoveryInterface
+	mapper meta.RESTMapper
+}
+
+// getDiscoveryClient :: gets client for discovering the API groups and resources served by the cluster.
+// The discovery responses are cached in memory for discoveryCacheTTL.
+func getDiscoveryClient(ctx context.Context, d *plugin.QueryData) (discovery.CachedDiscoveryInterface, error) {
+	clients, err := getDiscoveryClients(ctx, d)
+	if err != nil || clients == nil {
 		return nil, err
 	}
 
-	clientset, err := dynamic.NewForConfig(clusterConfig)
+	return clients.client, nil
+}
+
+// getRESTMapper returns a mapper to resolve the resources and the scope of the kinds served by the cluster.
+// The API discovery is performed lazily, and cached for discoveryCacheTTL.
+func getRESTMapper(ctx context.Context, d *plugin.QueryData) (meta.RESTMapper, error) {
+	clients, err := getDiscoveryClients(ctx, d)
+	if err != nil || clients == nil {
+		return nil, err
+	}
+
+	return clients.mapper, nil
+}
+
+func getDiscoveryClients(ctx context.Context, d *plugin.QueryData) (*discoveryClients, error) {
+	// have we already created and cached the clients?
+	serviceCacheKey := "getDiscoveryClients"
+
+	if cachedData, ok := d.ConnectionManager.Cache.Get(serviceCacheKey); ok {
+		return cachedData.(*discoveryClients), nil
+	}
+
+	clientset, err := GetNewClientset(ctx, d)
 	if err != nil {
-		plugin.Logger(ctx).Error("inClusterConfigCRDDynamic", "NewForConfig", err)
 		return nil, err
 	}
 
-	return clientset, nil
+	if clientset == nil {
+		return nil, nil
+	}
+
+	client := memory.NewMemCacheClient(clientset.Discovery())
+	clients := &discoveryClients{
+		client: client,
+		mapper: restmapper.NewDeferredDiscoveryRESTMapper(client),
+	}
+
+	// save clients in cache
+	d.ConnectionManager.Cache.SetWithTTL(serviceCacheKey, clients, discoveryCacheTTL)
+
+	return clients, nil
 }
 
 // Get kubernetes config based on environment variable and plugin config
@@ -352,84 +414,18 @@ func getK8Config(ctx context.Context, d *plugin.QueryData) (clientcmd.ClientConf
 	// get kubernetes config info
 	kubernetesConfig := GetConfig(d.Connection)
 
-	// Check for the sourceTypes argument in the config
-	// Default set to include values
-	var sources = All.ToSourceTypes()
-	if kubernetesConfig.SourceTypes != nil {
-		sources = kubernetesConfig.SourceTypes
-	}
-	// TODO: Remove once `SourceType` is obsolete
-	if kubernetesConfig.SourceTypes == nil && kubernetesConfig.SourceType != nil {
-		if *kubernetesConfig.SourceType != "all" { // if is all, sources is already set by default
-			sources = []string{*kubernetesConfig.SourceType}
-		}
-	}
+	sources := getSourceTypes(kubernetesConfig)
 
 	if !slices.Contains(sources, "deployed") {
 		plugin.Logger(ctx).Debug("getK8Config", "Returning nil for API server client.", "source_types", sources, "connection", d.Connection.Name)
 		return nil, nil
 	}
 
-	// If config_path contains inline kubeconfig YAML (not a file path), parse it directly without any disk I/O.
-	if kubernetesConfig.ConfigPath != nil {
-		if kubeconfig, ok, err := tryInlineKubeconfig(*kubernetesConfig.ConfigPath, kubernetesConfig.ConfigContext); err != nil {
-			return nil, err
-		} else if ok {
-			d.ConnectionManager.Cache.Set(cacheKey, kubeconfig)
-			return kubeconfig, nil
-		}
-	}
-
-	// Set default loader and overriding rules
-	loader := &clientcmd.ClientConfigLoadingRules{}
-	overrides := &clientcmd.ConfigOverrides{}
-
-	// variable to store paths for kubernetes config
-	// default kube config path
-	var configPaths = []string{"~/.kube/config"}
-
-	if kubernetesConfig.ConfigPath != nil {
-		configPaths = []string{*kubernetesConfig.ConfigPath}
-	} else if len(kubernetesConfig.ConfigPaths) > 0 {
-		plugin.Logger(ctx).Warn("config_paths parameter is deprecated and will be removed after 31st July 2023, please use config_path instead.")
-		configPaths = kubernetesConfig.ConfigPaths
-	} else if v := os.Getenv("KUBECONFIG"); v != "" {
-		configPaths = []string{v}
-	} else if v := os.Getenv("KUBE_CONFIG_PATH"); v != "" {
-		configPaths = []string{v}
-	} else if v := os.Getenv("KUBE_CONFIG_PATHS"); v != "" {
-		plugin.Logger(ctx).Warn("KUBE_CONFIG_PATHS parameter is deprecated and will be removed after 31st July 2023, please use KUBECONFIG or KUBE_CONFIG_PATH instead.")
-		configPaths = filepath.SplitList(v)
-	} else if v := os.Getenv("KUBERNETES_MASTER"); v != "" {
-		plugin.Logger(ctx).Warn("KUBERNETES_MASTER parameter is deprecated and will be removed after 31st July 2023, please use KUBECONFIG or KUBE_CONFIG_PATH instead.")
-		configPaths = []string{v}
-	}
-
-	if len(configPaths) > 0 {
-		expandedPaths := []string{}
-		for _, p := range configPaths {
-			path, err := homedir.Expand(p)
-			if err != nil {
-				return nil, err
-			}
-
-			expandedPaths = append(expandedPaths, path)
-		}
-
-		if len(expandedPaths) == 1 {
-			loader.ExplicitPath = expandedPaths[0]
-		} else {
-			loader.Precedence = expandedPaths
-		}
-
-		if kubernetesConfig.ConfigContext != nil {
-			overrides.CurrentContext = *kubernetesConfig.ConfigContext
-			overrides.Context = clientcmdapi.Context{}
-		}
+	kubeconfig, err := loadK8Config(ctx, kubernetesConfig)
+	if err != nil {
+		return nil, err
 	}
 
-	kubeconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
-
 	// save the config in cache
 	d.ConnectionManager.Cache.Set(cacheKey, kubeconfig)
 
@@ -450,33 +446,75 @@ func getK8ConfigRaw(ctx context.Context, cc *connection.ConnectionCache, c *plug
 	// get kubernetes config info
 	kubernetesConfig := GetConfig(c)
 
-	// Check for the sourceTypes argument in the config.
-	// Default set to include values.
-	var sources = All.ToSourceTypes()
-	if kubernetesConfig.SourceTypes != nil {
-		sources = kubernetesConfig.SourceTypes
-	}
-	// TODO: Remove once `SourceType` is obsolete
-	if kubernetesConfig.SourceTypes == nil && kubernetesConfig.SourceType != nil {
-		if *kubernetesConfig.SourceType != "all" { // if is all, sources is already set by default
-			sources = []string{*kubernetesConfig.SourceType}
-		}
-	}
+	sources := getSourceTypes(kubernetesConfig)
 
 	if !slices.Contains(sources, "deployed") {
 		plugin.Logger(ctx).Debug("getK8ConfigRaw", "Returning nil for API server client.", "source_types", sources, "connection", c.Name)
 		return nil, nil
 	}
 
+	kubeconfig, err := loadK8Config(ctx, kubernetesConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// save the config in cache
+	err = cc.Set(ctx, cacheKey, kubeconfig)
+	if err != nil {
+		logger.Error("getK8ConfigRaw", "cache-set", err)
+	}
+
+	return kubeconfig, nil
+}
+
+// tryInlineKubeconfig checks if configPath is inline YAML content (not a file path) using the pathOrContents.
+// If inline, it parses the content directly and returns the ClientConfig.
+// Returns ok=false if the value is a file path (caller should fall through to the standard loader).
+func tryInlineKubeconfig(configPath string, configContext *string) (clientcmd.ClientConfig, bool, error) {
+	_, isInline, err := pathOrContents(configPath)
+	if err != nil {
+		return nil, false, err
+	}
+	if !isInline {
+		return nil, false, nil
+	}
+
+	kubeconfig, err := clientcmd.NewClientConfigFromBytes([]byte(configPath))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse inline kubeconfig from config_path: %w", err)
+	}
+
+	if configContext != nil {
+		rawConfig, err := kubeconfig.RawConfig()
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get raw config from inline kubeconfig: %w", err)
+		}
+		rawConfig.CurrentContext = *configContext
+		overrides := &clientcmd.ConfigOverrides{
+			CurrentContext: *configContext,
+			Context:        clientcmdapi.Context{},
+		}
+		kubeconfig = clientcmd.NewNonInteractiveClientConfig(rawConfig, *configContext, overrides, nil)
+	}
+
+	return kubeconfig, true, nil
+}
+
+// loadK8Config loads the kubernetes config from the connection settings, the inline kubeconfig or the kubeconfig files.
+// It is not cached, so that the clients can be rebuilt from the latest kubeconfig, e.g. after it has been rotated.
+func loadK8Config(ctx context.Context, kubernetesConfig kubernetesConfig) (clientcmd.ClientConfig, error) {
+	// If the cluster endpoint is set explicitly, build the config from the connection settings without any kubeconfig.
+	if kubeconfig, ok, err := tryExplicitClusterConfig(kubernetesConfig); err != nil {
+		return nil, err
+	} else if ok {
+		return kubeconfig, nil
+	}
+
 	// If config_path contains inline kubeconfig YAML (not a file path), parse it directly without any disk I/O.
 	if kubernetesConfig.ConfigPath != nil {
 		if kubeconfig, ok, err := tryInlineKubeconfig(*kubernetesConfig.ConfigPath, kubernetesConfig.ConfigContext); err != nil {
 			return nil, err
 		} else if ok {
-			cacheErr := cc.Set(ctx, cacheKey, kubeconfig)
-			if cacheErr != nil {
-				logger.Error("getK8ConfigRaw", "cache-set", cacheErr)
-			}
 			return kubeconfig, nil
 		}
 	}
@@ -529,48 +567,100 @@ func getK8ConfigRaw(ctx context.Context, cc *connection.ConnectionCache, c *plug
 		}
 	}
 
-	kubeconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
-
-	// save the config in cache
-	err := cc.Set(ctx, cacheKey, kubeconfig)
-	if err != nil {
-		logger.Error("getK8ConfigRaw", "cache-set", err)
-	}
-
-	return kubeconfig, nil
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides), nil
 }
 
-// tryInlineKubeconfig checks if configPath is inline YAML content (not a file path) using the pathOrContents.
-// If inline, it parses the content directly and returns the ClientConfig.
-// Returns ok=false if the value is a file path (caller should fall through to the standard loader).
-func tryInlineKubeconfig(configPath string, configContext *string) (clientcmd.ClientConfig, bool, error) {
-	_, isInline, err := pathOrContents(configPath)
-	if err != nil {
-		return nil, false, err
+// tryExplicitClusterConfig builds the config from the cluster endpoint, credentials and TLS settings of the connection config,
+// falling back to the KUBE_* environment variables used by the Terraform provider for the settings which are not set.
+// Returns ok=false if no host is set (caller should fall through to the kubeconfig).
+func tryExplicitClusterConfig(kubernetesConfig kubernetesConfig) (clientcmd.ClientConfig, bool, error) {
+	host := stringValueOrEnv(kubernetesConfig.Host, "")
+
+	// The KUBE_HOST environment variable only takes precedence over the kubeconfig files if these are not set in the connection config
+	if host == "" && kubernetesConfig.ConfigPath == nil && len(kubernetesConfig.ConfigPaths) == 0 {
+		host = os.Getenv("KUBE_HOST")
 	}
-	if !isInline {
+
+	if host == "" {
+		if kubernetesConfig.Token != nil || kubernetesConfig.TokenFile != nil || kubernetesConfig.ClientCertificate != nil || kubernetesConfig.ClientKey != nil ||
+			kubernetesConfig.ClusterCACertificate != nil || kubernetesConfig.InsecureSkipTLSVerify != nil || kubernetesConfig.TLSServerName != nil || kubernetesConfig.ProxyURL != nil {
+			return nil, false, errors.New("token, token_file, client_certificate, client_key, cluster_ca_certificate, insecure_skip_tls_verify, tls_server_name and proxy_url require host to be set")
+		}
 		return nil, false, nil
 	}
 
-	kubeconfig, err := clientcmd.NewClientConfigFromBytes([]byte(configPath))
-	if err != nil {
-		return nil, false, fmt.Errorf("failed to parse inline kubeconfig from config_path: %w", err)
+	cluster := clientcmdapi.NewCluster()
+	cluster.Server = host
+	cluster.TLSServerName = stringValueOrEnv(kubernetesConfig.TLSServerName, "KUBE_TLS_SERVER_NAME")
+	cluster.ProxyURL = stringValueOrEnv(kubernetesConfig.ProxyURL, "KUBE_PROXY_URL")
+	cluster.CertificateAuthority, cluster.CertificateAuthorityData = certificateFileOrData(stringValueOrEnv(kubernetesConfig.ClusterCACertificate, "KUBE_CLUSTER_CA_CERT_DATA"))
+	if kubernetesConfig.InsecureSkipTLSVerify != nil {
+		cluster.InsecureSkipTLSVerify = *kubernetesConfig.InsecureSkipTLSVerify
+	} else if v := os.Getenv("KUBE_INSECURE"); v != "" {
+		insecure, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid KUBE_INSECURE value %q: %w", v, err)
+		}
+		cluster.InsecureSkipTLSVerify = insecure
 	}
 
-	if configContext != nil {
-		rawConfig, err := kubeconfig.RawConfig()
+	// The token takes precedence over the token file if both are set
+	authInfo := clientcmdapi.NewAuthInfo()
+	authInfo.Token = stringValueOrEnv(kubernetesConfig.Token, "KUBE_TOKEN")
+	authInfo.TokenFile = stringValueOrEnv(kubernetesConfig.TokenFile, "KUBE_TOKEN_FILE")
+	if authInfo.TokenFile != "" {
+		path, err := homedir.Expand(authInfo.TokenFile)
 		if err != nil {
-			return nil, false, fmt.Errorf("failed to get raw config from inline kubeconfig: %w", err)
-		}
-		rawConfig.CurrentContext = *configContext
-		overrides := &clientcmd.ConfigOverrides{
-			CurrentContext: *configContext,
-			Context:        clientcmdapi.Context{},
+			return nil, false, err
 		}
-		kubeconfig = clientcmd.NewNonInteractiveClientConfig(rawConfig, *configContext, overrides, nil)
+		authInfo.TokenFile = path
 	}
+	authInfo.ClientCertificate, authInfo.ClientCertificateData = certificateFileOrData(stringValueOrEnv(kubernetesConfig.ClientCertificate, "KUBE_CLIENT_CERT_DATA"))
+	authInfo.ClientKey, authInfo.ClientKeyData = certificateFileOrData(stringValueOrEnv(kubernetesConfig.ClientKey, "KUBE_CLIENT_KEY_DATA"))
 
-	return kubeconfig, true, nil
+	// The context is named after the host, unless a context name is set in the connection config
+	contextName := host
+	if kubernetesConfig.ConfigContext != nil {
+		contextName = *kubernetesConfig.ConfigContext
+	}
+	kubeContext := clientcmdapi.NewContext()
+	kubeContext.Cluster = contextName
+	kubeContext.AuthInfo = contextName
+
+	config := clientcmdapi.NewConfig()
+	config.Clusters[contextName] = cluster
+	config.AuthInfos[contextName] = authInfo
+	config.Contexts[contextName] = kubeContext
+	config.CurrentContext = contextName
+
+	return clientcmd.NewNonInteractiveClientConfig(*config, contextName, &clientcmd.ConfigOverrides{}, nil), true, nil
+}
+
+// stringValueOrEnv returns the value set in the connection config, or else the value of the given environment variable
+func stringValueOrEnv(value *string, env string) string {
+	if value != nil {
+		return *value
+	}
+	if env == "" {
+		return ""
+	}
+	return os.Getenv(env)
+}
+
+// certificateFileOrData checks if the given certificate or key is PEM-encoded content or a file path,
+// and returns it as the file path or the data of the kubeconfig fields accordingly.
+func certificateFileOrData(value string) (string, []byte) {
+	if value == "" {
+		return "", nil
+	}
+	if strings.Contains(value, "-----BEGIN") {
+		return "", []byte(value)
+	}
+	// Expand ~ if present, the file is read by the client
+	if path, err := homedir.Expand(value); err == nil {
+		return path, nil
+	}
+	return value, nil
 }
 
 // pathOrContents checks if the given string is a file path or inline content.
@@ -757,6 +847,9 @@ type parsedContent struct {
 	SourceType string
 	StartLine  int
 	EndLine    int
+
+	// Node is the YAML node of the object in the manifest file, used to locate its fields
+	Node *goYaml.Node
 }
 
 // Returns the manifest file content based on the kind provided
@@ -767,6 +860,23 @@ func fetchResourceFromManifestFileByKind(ctx context.Context, d *plugin.QueryDat
 	}
 	var data []parsedContent
 
+	parsedContents, err := getAllParsedContent(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, content := range parsedContents {
+		if content.Kind == kind && isNamespaceIncluded(d, parsedContentNamespace(content)) {
+			data = append(data, content)
+		}
+	}
+
+	return data, nil
+}
+
+// getAllParsedContent returns the resources of all the sources other than the cluster, i.e. the manifest files,
+// the rendered Helm templates, the manifests of the deployed Helm releases and the cluster snapshots
+func getAllParsedContent(ctx context.Context, d *plugin.QueryData) ([]parsedContent, error) {
 	// Get parsed content from manifest files
 	parsedContents, err := getParsedManifestFileContent(ctx, d)
 	if err != nil {
@@ -779,14 +889,23 @@ func fetchResourceFromManifestFileByKind(ctx context.Context, d *plugin.QueryDat
 		return nil, err
 	}
 
-	parsedContents = append(parsedContents, renderedTemplateContents...)
-	for _, content := range parsedContents {
-		if content.Kind == kind {
-			data = append(data, content)
-		}
+	// Get parsed content from the manifests of the deployed Helm releases
+	helmReleaseContents, err := getHelmReleaseManifestContent(ctx, d)
+	if err != nil {
+		return nil, err
 	}
 
-	return data, nil
+	// Get parsed content from the cluster snapshots
+	snapshotContents, err := getSnapshotContent(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedContents = append(parsedContents, renderedTemplateContents...)
+	parsedContents = append(parsedContents, helmReleaseContents...)
+	parsedContents = append(parsedContents, snapshotContents...)
+
+	return parsedContents, nil
 }
 
 // Get the parsed contents of the given files.
@@ -822,38 +941,27 @@ func parsedManifestFileContentUncached(ctx context.Context, d *plugin.QueryData,
 			return nil, err
 		}
 
-		// Check for the start of the document
-		pos := 0
-		for _, resource := range yamlDocSeparator.Split(string(content), -1) {
-			// Skip empty documents, `Decode` will fail on them
-			// Also, increment the pos to include the separator position (e.g. ---)
-			if len(resource) == 0 {
-				pos++
-				continue
-			}
-
-			// Calculate the length of the YAML resource block
-			blockLength := strings.Split(strings.ReplaceAll(resource, " ", ""), "\n")
+		// The positions of the objects are taken from the YAML node tree.
+		// Since it is stricter than the decoder of the objects, e.g. on duplicate keys, fall back to the objects without positions.
+		documents, err := decodeManifestDocuments(content)
+		if err != nil {
+			plugin.Logger(ctx).Warn("parsedManifestFileContentUncached", "failed to parse the positions of the content", err, "path", path)
 
-			// Remove the extra lines added during the split operation based on the separator
-			blockLength = blockLength[:len(blockLength)-1]
-			if blockLength[0] == "" {
-				blockLength = blockLength[1:]
+			documents, err = decodeManifestDocumentsWithoutPositions(content)
+			if err != nil {
+				plugin.Logger(ctx).Error("parsedManifestFileContentUncached", "failed to unmarshal the content", err, "path", path)
+				return nil, err
 			}
+		}
+
+		for _, document := range documents {
+			obj := document.Object
 
 			// skip if no kind defined
-			if !(strings.Contains(resource, "kind:") || strings.Contains(resource, "\"kind\":")) {
-				pos = pos + len(blockLength) + 1
+			if obj.GetKind() == "" {
 				continue
 			}
 
-			obj := &unstructured.Unstructured{}
-			err = yaml.Unmarshal([]byte(resource), obj)
-			if err != nil {
-				plugin.Logger(ctx).Error("parsedManifestFileContentUncached", "failed to unmarshal the content", err, "path", path)
-				return nil, err
-			}
-
 			obj.SetAPIVersion(obj.GetAPIVersion())
 			obj.SetKind(obj.GetKind())
 			gvk := obj.GetObjectKind().GroupVersionKind()
@@ -875,36 +983,51 @@ func parsedManifestFileContentUncached(ctx context.Context, d *plugin.QueryData,
 				Kind:       obj.GetKind(),
 				Path:       path,
 				SourceType: "manifest",
-				StartLine:  pos + 1, // Since starts from 0
-				EndLine:    pos + len(blockLength),
+				StartLine:  document.StartLine,
+				EndLine:    document.EndLine,
+				Node:       document.Node,
 			})
-
-			// Increment the position by the length of the block
-			// the value is added with 1 to include the separator
-			pos = pos + len(blockLength) + 1
 		}
 	}
 
 	return parsedContents, nil
 }
 
+// expandListItems returns the items of a list, e.g. the output of `kubectl get -o yaml` with `kind: List`, or
+// a typed list such as `kind: PodList`. The kind and apiVersion of the items are inherited from the typed lists
+// if missing. Any other object is returned as is.
+func expandListItems(obj *unstructured.Unstructured) []*unstructured.Unstructured {
+	if !strings.HasSuffix(obj.GetKind(), "List") || !obj.IsList() {
+		return []*unstructured.Unstructured{obj}
+	}
+
+	itemKind := strings.TrimSuffix(obj.GetKind(), "List")
+
+	var items []*unstructured.Unstructured
+	_ = obj.EachListItem(func(o runtime.Object) error {
+		item, ok := o.(*unstructured.Unstructured)
+		if !ok {
+			return nil
+		}
+		if item.GetKind() == "" && itemKind != "" {
+			item.SetKind(itemKind)
+		}
+		if item.GetAPIVersion() == "" {
+			item.SetAPIVersion(obj.GetAPIVersion())
+		}
+		items = append(items, item)
+		return nil
+	})
+
+	return items
+}
+
 // Returns the list of file paths/glob patterns after resolving all the given manifest file paths.
 func resolveManifestFilePaths(ctx context.Context, d *plugin.QueryData) ([]string, error) {
 	// Read the config
 	kubernetesConfig := GetConfig(d.Connection)
 
-	// Check for the sourceTypes argument in the config. Valid values are: "deployed", "manifest" and "helm".
-	// Default set to include values.
-	var sources = All.ToSourceTypes()
-	if kubernetesConfig.SourceTypes != nil {
-		sources = kubernetesConfig.SourceTypes
-	}
-	// TODO: Remove once `SourceType` is obsolete
-	if kubernetesConfig.SourceTypes == nil && kubernetesConfig.SourceType != nil {
-		if *kubernetesConfig.SourceType != "all" { // if is all, sources is already set by default
-			sources = []string{*kubernetesConfig.SourceType}
-		}
-	}
+	sources := getSourceTypes(kubernetesConfig)
 
 	// Return no files if manifest not set in source_types or if we omit setting any file paths
 	if !slices.Contains(sources, "manifest") {
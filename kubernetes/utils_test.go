@@ -3,7 +3,10 @@ package kubernetes
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+
+	"k8s.io/client-go/rest"
 )
 
 const inlineKubeconfig = `apiVersion: v1
@@ -125,3 +128,128 @@ func TestTryInlineKubeconfig(t *testing.T) {
 		t.Errorf("CurrentContext = %q, want %q", raw.CurrentContext, override)
 	}
 }
+
+func TestGetImpersonationConfig(t *testing.T) {
+	user := "jane"
+	uid := "1234"
+
+	impersonate, err := getImpersonationConfig(kubernetesConfig{
+		ImpersonateUser:   &user,
+		ImpersonateGroups: []string{"team-a"},
+		ImpersonateUID:    &uid,
+		ImpersonateExtra:  map[string][]string{"scopes": {"view"}},
+	})
+	if err != nil {
+		t.Fatalf("getImpersonationConfig() error = %v", err)
+	}
+	expected := rest.ImpersonationConfig{UserName: "jane", UID: "1234", Groups: []string{"team-a"}, Extra: map[string][]string{"scopes": {"view"}}}
+	if !reflect.DeepEqual(impersonate, expected) {
+		t.Errorf("getImpersonationConfig() = %+v, want %+v", impersonate, expected)
+	}
+
+	// No impersonation by default
+	if impersonate, err := getImpersonationConfig(kubernetesConfig{}); err != nil || impersonate.UserName != "" {
+		t.Errorf("getImpersonationConfig(empty) = %+v, %v; want no impersonation", impersonate, err)
+	}
+
+	// The groups cannot be impersonated without a user
+	if _, err := getImpersonationConfig(kubernetesConfig{ImpersonateGroups: []string{"team-a"}}); err == nil {
+		t.Errorf("getImpersonationConfig(groups only) expected an error")
+	}
+}
+
+func TestTryExplicitClusterConfig(t *testing.T) {
+	for _, env := range []string{"KUBE_HOST", "KUBE_TOKEN", "KUBE_TOKEN_FILE", "KUBE_CLIENT_CERT_DATA", "KUBE_CLIENT_KEY_DATA", "KUBE_CLUSTER_CA_CERT_DATA", "KUBE_INSECURE", "KUBE_TLS_SERVER_NAME", "KUBE_PROXY_URL"} {
+		t.Setenv(env, "")
+	}
+
+	host := "https://10.0.0.1:6443"
+	token := "secret-token"
+	ca := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	serverName := "kubernetes.default"
+
+	kubeconfig, ok, err := tryExplicitClusterConfig(kubernetesConfig{
+		Host:                 &host,
+		Token:                &token,
+		ClusterCACertificate: &ca,
+		TLSServerName:        &serverName,
+	})
+	if err != nil || !ok {
+		t.Fatalf("tryExplicitClusterConfig() = %v, %v; want a config", ok, err)
+	}
+	restconfig, err := kubeconfig.ClientConfig()
+	if err != nil {
+		t.Fatalf("ClientConfig() error = %v", err)
+	}
+	if restconfig.Host != host || restconfig.BearerToken != token || string(restconfig.TLSClientConfig.CAData) != ca || restconfig.TLSClientConfig.ServerName != serverName {
+		t.Errorf("ClientConfig() = %+v, want the explicit settings", restconfig)
+	}
+	if rawConfig, _ := kubeconfig.RawConfig(); rawConfig.CurrentContext != host {
+		t.Errorf("RawConfig().CurrentContext = %q, want %q", rawConfig.CurrentContext, host)
+	}
+
+	// The settings which are not set in the connection config are read from the environment
+	t.Setenv("KUBE_TOKEN", "env-token")
+	t.Setenv("KUBE_INSECURE", "true")
+	kubeconfig, _, err = tryExplicitClusterConfig(kubernetesConfig{Host: &host})
+	if err != nil {
+		t.Fatalf("tryExplicitClusterConfig(env) error = %v", err)
+	}
+	if restconfig, err := kubeconfig.ClientConfig(); err != nil || restconfig.BearerToken != "env-token" || !restconfig.TLSClientConfig.Insecure {
+		t.Errorf("ClientConfig(env) = %+v, %v; want the environment settings", restconfig, err)
+	}
+
+	// The KUBE_HOST environment variable does not override a config_path set in the connection config
+	t.Setenv("KUBE_HOST", host)
+	configPath := "~/.kube/config"
+	if _, ok, err := tryExplicitClusterConfig(kubernetesConfig{ConfigPath: &configPath}); ok || err != nil {
+		t.Errorf("tryExplicitClusterConfig(config_path) = %v, %v; want the kubeconfig to be used", ok, err)
+	}
+
+	// The credentials cannot be set without a host
+	t.Setenv("KUBE_HOST", "")
+	if _, _, err := tryExplicitClusterConfig(kubernetesConfig{Token: &token}); err == nil {
+		t.Errorf("tryExplicitClusterConfig(token only) expected an error")
+	}
+}
+
+func TestCertificateFileOrData(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		wantPath string
+		wantData string
+	}{
+		{name: "empty", value: ""},
+		{name: "PEM content", value: "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----", wantData: "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----"},
+		{name: "file path", value: "/etc/kubernetes/pki/ca.crt", wantPath: "/etc/kubernetes/pki/ca.crt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, data := certificateFileOrData(tt.value)
+			if path != tt.wantPath || string(data) != tt.wantData {
+				t.Errorf("certificateFileOrData(%q) = %q, %q; want %q, %q", tt.value, path, data, tt.wantPath, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestGetSourceTypes(t *testing.T) {
+	all, manifest := "all", "manifest"
+	tests := []struct {
+		name     string
+		config   kubernetesConfig
+		expected []string
+	}{
+		{"default", kubernetesConfig{}, []string{"deployed", "helm", "manifest"}},
+		{"source_types", kubernetesConfig{SourceTypes: []string{"snapshot"}}, []string{"snapshot"}},
+		{"source_type", kubernetesConfig{SourceType: &manifest}, []string{"manifest"}},
+		{"source_type all", kubernetesConfig{SourceType: &all}, []string{"deployed", "helm", "manifest"}},
+		{"source_types over source_type", kubernetesConfig{SourceTypes: []string{"deployed"}, SourceType: &manifest}, []string{"deployed"}},
+	}
+	for _, tt := range tests {
+		if got := getSourceTypes(tt.config); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: getSourceTypes() = %v, expected %v", tt.name, got, tt.expected)
+		}
+	}
+}